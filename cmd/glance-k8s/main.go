@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"expvar"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -29,5 +31,29 @@ func run() error {
 		return fmt.Errorf("could not connect to cluster: %w", err)
 	}
 
-	return http.ListenAndServe(":8080", extension.New(client))
+	handler, err := extension.New(client)
+	if err != nil {
+		return fmt.Errorf("could not create extension: %w", err)
+	}
+
+	go client.Run(context.Background())
+
+	if addr := os.Getenv("GLANCE_DEBUG_ADDR"); addr != "" {
+		go serveDebug(addr)
+	}
+
+	return http.ListenAndServe(":8080", handler)
+}
+
+// serveDebug serves expvar on a separate listener, so it is not exposed
+// alongside the widgets.
+func serveDebug(addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	slog.Info("serving debug endpoints", slog.String("addr", addr))
+
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		slog.Error("could not serve debug endpoints", slog.Any("err", err))
+	}
 }
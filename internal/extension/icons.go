@@ -0,0 +1,368 @@
+package extension
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"golang.org/x/sync/singleflight"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// iconMaxSize limits the size of a single icon fetched from upstream.
+	iconMaxSize = 1 << 20
+	// iconMemoryCacheBytes limits the total size of icons kept in memory when
+	// no cache directory is configured.
+	iconMemoryCacheBytes = 32 << 20
+	// iconFailureTTL is how long a failed fetch is remembered, so missing
+	// icons or an unreachable CDN do not block every render.
+	iconFailureTTL = 5 * time.Minute
+	// iconFailureEntries limits the number of remembered failures, since
+	// clients may request any icon name.
+	iconFailureEntries = 1024
+
+	// fallbackIconShorthand and fallbackIconName make up the icon rendered
+	// for unknown shorthands.
+	fallbackIconShorthand = "di"
+	fallbackIconName      = "kubernetes"
+)
+
+// defaultIconShorthands are the url templates of the shorthands supported by
+// glance. The placeholders {name} and {extension} are replaced when resolving.
+var defaultIconShorthands = iconShorthands{
+	"si":  "https://cdn.jsdelivr.net/npm/simple-icons@latest/icons/{name}.svg",
+	"di":  "https://cdn.jsdelivr.net/gh/homarr-labs/dashboard-icons/{extension}/{name}.{extension}",
+	"mdi": "https://cdn.jsdelivr.net/npm/@mdi/svg@latest/svg/{name}.svg",
+	"sh":  "https://cdn.jsdelivr.net/gh/selfhst/icons/{extension}/{name}.{extension}",
+}
+
+// iconShorthands maps shorthands to url templates.
+type iconShorthands map[string]string
+
+// loadIconShorthands merges the default shorthands with the YAML file at
+// filename. Entries of the file override defaults or add custom shorthands.
+func loadIconShorthands(filename string) (iconShorthands, error) {
+	shorthands := maps.Clone(defaultIconShorthands)
+
+	if filename == "" {
+		return shorthands, nil
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("could not read icon shorthands: %w", err)
+	}
+
+	var custom iconShorthands
+	if err := yaml.Unmarshal(content, &custom); err != nil {
+		return nil, fmt.Errorf("could not parse icon shorthands: %w", err)
+	}
+
+	for shorthand, urlTemplate := range custom {
+		if !iconShorthandPattern.MatchString(shorthand) {
+			return nil, fmt.Errorf("invalid icon shorthand %q", shorthand)
+		}
+
+		shorthands[shorthand] = urlTemplate
+	}
+
+	return shorthands, nil
+}
+
+func (s iconShorthands) resolve(shorthand, name, extension string) (string, bool) {
+	urlTemplate, ok := s[shorthand]
+	if !ok {
+		return "", false
+	}
+
+	replacer := strings.NewReplacer("{name}", name, "{extension}", extension)
+	return replacer.Replace(urlTemplate), true
+}
+
+var (
+	iconShorthandPattern = regexp.MustCompile(`^[a-z][\w-]*$`)
+	iconFilePattern      = regexp.MustCompile(`^[^\s./]+\.\w+$`)
+)
+
+// iconProxy serves shorthand icons from a pre-seeded local directory or a
+// cache and fetches them from their upstream CDN on a miss. When publicUrl
+// is set, shorthand icons are rewritten to point at the proxy.
+type iconProxy struct {
+	publicUrl  string
+	localDir   string
+	cache      iconCache
+	shorthands iconShorthands
+	client     *http.Client
+	flight     singleflight.Group
+	failures   iconFailures
+}
+
+func newIconProxy(shorthands iconShorthands) *iconProxy {
+	var cache iconCache = newMemoryIconCache(iconMemoryCacheBytes)
+	if dir := os.Getenv("GLANCE_ICON_CACHE_DIR"); dir != "" {
+		cache = diskIconCache(dir)
+	}
+
+	return &iconProxy{
+		publicUrl:  strings.TrimSuffix(os.Getenv("GLANCE_ICON_PROXY_URL"), "/"),
+		localDir:   os.Getenv("GLANCE_ICON_DIR"),
+		cache:      cache,
+		shorthands: shorthands,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// proxyUrl returns the url of the icon on the proxy, if the proxy is
+// enabled and the name can be used as a path segment without escaping.
+func (p *iconProxy) proxyUrl(shorthand, name, extension string) (string, bool) {
+	file := name + "." + extension
+
+	if p.publicUrl == "" || !iconFilePattern.MatchString(file) || url.PathEscape(file) != file {
+		return "", false
+	}
+
+	return fmt.Sprintf("%s/icons/%s/%s", p.publicUrl, shorthand, file), true
+}
+
+func (p *iconProxy) handler() echo.HandlerFunc {
+	return func(ctx *echo.Context) error {
+		shorthand, file := ctx.Param("shorthand"), ctx.Param("file")
+		if !iconShorthandPattern.MatchString(shorthand) || !iconFilePattern.MatchString(file) {
+			return echo.ErrNotFound
+		}
+
+		icon, err := p.icon(shorthand, file)
+		if err != nil {
+			return err
+		}
+
+		ctx.Response().Header().Set("Cache-Control", "public, max-age=86400")
+		return ctx.Blob(http.StatusOK, iconContentType(file), icon)
+	}
+}
+
+func (p *iconProxy) icon(shorthand, file string) ([]byte, error) {
+	key := filepath.Join(shorthand, file)
+
+	if p.localDir != "" {
+		icon, err := os.ReadFile(filepath.Join(p.localDir, key))
+		if err == nil {
+			return icon, nil
+		}
+
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	if icon, ok := p.cache.get(key); ok {
+		return icon, nil
+	}
+
+	if err := p.failures.get(key); err != nil {
+		return nil, err
+	}
+
+	result, err, _ := p.flight.Do(key, func() (any, error) {
+		icon, err := p.fetch(shorthand, file)
+		if err != nil {
+			p.failures.put(key, err)
+			return nil, err
+		}
+
+		if err := p.cache.put(key, icon); err != nil {
+			slog.Warn("could not cache icon", slog.String("icon", key), slog.Any("err", err))
+		}
+
+		return icon, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.([]byte), nil
+}
+
+func (p *iconProxy) fetch(shorthand, file string) ([]byte, error) {
+	name, extension, _ := strings.Cut(file, ".")
+
+	upstreamUrl, ok := p.shorthands.resolve(shorthand, name, extension)
+	if !ok {
+		return nil, echo.ErrNotFound
+	}
+
+	slog.Debug("fetching icon", slog.String("url", upstreamUrl))
+
+	res, err := p.client.Get(upstreamUrl)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch icon: %w", err)
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return nil, echo.ErrNotFound
+	case res.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("could not fetch icon %q: unexpected status %d", upstreamUrl, res.StatusCode)
+	}
+
+	icon, err := io.ReadAll(io.LimitReader(res.Body, iconMaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch icon: %w", err)
+	}
+
+	if len(icon) > iconMaxSize {
+		return nil, fmt.Errorf("could not fetch icon %q: larger than %d bytes", upstreamUrl, iconMaxSize)
+	}
+
+	return icon, nil
+}
+
+func iconContentType(file string) string {
+	if contentType := mime.TypeByExtension(filepath.Ext(file)); contentType != "" {
+		return contentType
+	}
+
+	return "application/octet-stream"
+}
+
+type iconCache interface {
+	get(key string) ([]byte, bool)
+	put(key string, icon []byte) error
+}
+
+// iconFailures remembers failed fetches for iconFailureTTL, up to
+// iconFailureEntries. Failures share the same TTL, so the oldest failure
+// is always the first to expire and the first to be evicted.
+type iconFailures struct {
+	mu       sync.Mutex
+	failures map[string]*list.Element
+	order    list.List
+}
+
+type iconFailure struct {
+	key       string
+	err       error
+	expiresAt time.Time
+}
+
+// get returns the error of the last failed fetch of key, if it did not
+// expire yet.
+func (f *iconFailures) get(key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	element, ok := f.failures[key]
+	if !ok {
+		return nil
+	}
+
+	failure := element.Value.(*iconFailure)
+	if time.Now().After(failure.expiresAt) {
+		f.remove(element)
+		return nil
+	}
+
+	return failure.err
+}
+
+func (f *iconFailures) put(key string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.failures == nil {
+		f.failures = make(map[string]*list.Element)
+	}
+
+	if element, ok := f.failures[key]; ok {
+		f.remove(element)
+	}
+
+	now := time.Now()
+	for oldest := f.order.Front(); oldest != nil && now.After(oldest.Value.(*iconFailure).expiresAt); oldest = f.order.Front() {
+		f.remove(oldest)
+	}
+
+	if f.order.Len() >= iconFailureEntries {
+		f.remove(f.order.Front())
+	}
+
+	f.failures[key] = f.order.PushBack(&iconFailure{key: key, err: err, expiresAt: now.Add(iconFailureTTL)})
+}
+
+func (f *iconFailures) remove(element *list.Element) {
+	f.order.Remove(element)
+	delete(f.failures, element.Value.(*iconFailure).key)
+}
+
+// memoryIconCache keeps icons in memory up to a total of maxBytes. Once
+// full, additional icons are served but not cached.
+type memoryIconCache struct {
+	mu       sync.RWMutex
+	icons    map[string][]byte
+	size     int
+	maxBytes int
+}
+
+func newMemoryIconCache(maxBytes int) *memoryIconCache {
+	return &memoryIconCache{
+		icons:    make(map[string][]byte),
+		maxBytes: maxBytes,
+	}
+}
+
+func (c *memoryIconCache) get(key string) ([]byte, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	icon, ok := c.icons[key]
+	return icon, ok
+}
+
+func (c *memoryIconCache) put(key string, icon []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.icons[key]; ok || c.size+len(icon) > c.maxBytes {
+		return nil
+	}
+
+	c.icons[key] = icon
+	c.size += len(icon)
+
+	return nil
+}
+
+// diskIconCache stores icons as files below a directory, which survives
+// restarts when backed by a volume.
+type diskIconCache string
+
+func (c diskIconCache) get(key string) ([]byte, bool) {
+	icon, err := os.ReadFile(filepath.Join(string(c), key))
+	return icon, err == nil
+}
+
+func (c diskIconCache) put(key string, icon []byte) error {
+	filename := filepath.Join(string(c), key)
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, icon, 0o644)
+}
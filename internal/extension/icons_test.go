@@ -0,0 +1,201 @@
+package extension
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestIconProxy(t *testing.T, upstream *httptest.Server) *iconProxy {
+	t.Helper()
+
+	return &iconProxy{
+		cache:      newMemoryIconCache(iconMemoryCacheBytes),
+		shorthands: iconShorthands{"di": upstream.URL + "/di/{name}.{extension}"},
+		client:     upstream.Client(),
+	}
+}
+
+func TestIconProxy_FetchesOnceAndCaches(t *testing.T) {
+	var calls int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.URL.Path != "/di/immich.svg" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("<svg/>"))
+	}))
+	defer upstream.Close()
+
+	proxy := newTestIconProxy(t, upstream)
+
+	for i := 0; i < 3; i++ {
+		icon, err := proxy.icon("di", "immich.svg")
+		if err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+		if string(icon) != "<svg/>" {
+			t.Fatalf("call %d: got %q", i, icon)
+		}
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("upstream called %d times, want 1", got)
+	}
+
+	if _, err := proxy.icon("di", "missing.svg"); err == nil {
+		t.Fatalf("expected error for missing icon")
+	}
+
+	if _, err := proxy.icon("xx", "immich.svg"); err == nil {
+		t.Fatalf("expected error for unknown shorthand")
+	}
+}
+
+func TestIconProxy_RemembersFailures(t *testing.T) {
+	var calls int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.NotFound(w, r)
+	}))
+	defer upstream.Close()
+
+	proxy := newTestIconProxy(t, upstream)
+
+	for i := 0; i < 3; i++ {
+		if _, err := proxy.icon("di", "missing.svg"); err == nil {
+			t.Fatalf("call %d: expected error for missing icon", i)
+		}
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("upstream called %d times, want 1", got)
+	}
+}
+
+func TestIconProxy_RejectsOversizedIcons(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(make([]byte, iconMaxSize+1))
+	}))
+	defer upstream.Close()
+
+	proxy := newTestIconProxy(t, upstream)
+
+	if _, err := proxy.icon("di", "huge.svg"); err == nil {
+		t.Fatalf("expected error for oversized icon")
+	}
+
+	if _, ok := proxy.cache.get("di/huge.svg"); ok {
+		t.Fatalf("oversized icon was cached")
+	}
+}
+
+func TestMemoryIconCache_BoundedByBytes(t *testing.T) {
+	cache := newMemoryIconCache(10)
+
+	_ = cache.put("a", make([]byte, 6))
+	_ = cache.put("b", make([]byte, 6))
+	_ = cache.put("c", make([]byte, 4))
+
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, ok := cache.get(key); ok != want {
+			t.Errorf("get(%q) cached = %v, want %v", key, ok, want)
+		}
+	}
+}
+
+func TestIconFailures_Bounded(t *testing.T) {
+	var failures iconFailures
+
+	for i := range iconFailureEntries + 10 {
+		failures.put(fmt.Sprintf("di/%d.svg", i), errors.New("not found"))
+	}
+
+	if got := failures.order.Len(); got != iconFailureEntries {
+		t.Errorf("entries = %d, want %d", got, iconFailureEntries)
+	}
+
+	if failures.get("di/0.svg") != nil {
+		t.Error("oldest failure was not evicted")
+	}
+
+	if failures.get(fmt.Sprintf("di/%d.svg", iconFailureEntries+9)) == nil {
+		t.Error("latest failure was evicted")
+	}
+
+	for element := failures.order.Front(); element != nil; element = element.Next() {
+		element.Value.(*iconFailure).expiresAt = time.Now().Add(-time.Second)
+	}
+
+	failures.put("di/new.svg", errors.New("not found"))
+
+	if got := len(failures.failures); got != 1 {
+		t.Errorf("entries = %d after pruning, want 1", got)
+	}
+}
+
+func TestIconProxy_PrefersLocalDirectory(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected upstream request %s", r.URL)
+	}))
+	defer upstream.Close()
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "di"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "di", "nas.png"), []byte("png"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	proxy := newTestIconProxy(t, upstream)
+	proxy.localDir = dir
+
+	icon, err := proxy.icon("di", "nas.png")
+	if err != nil || string(icon) != "png" {
+		t.Fatalf("got %q err %v", icon, err)
+	}
+}
+
+func TestIconTemplateFunc(t *testing.T) {
+	icons := &iconProxy{shorthands: iconShorthands{
+		"di":     defaultIconShorthands["di"],
+		"mdi":    defaultIconShorthands["mdi"],
+		"mirror": "https://icons.example.org/{name}.{extension}",
+	}}
+	icon := iconTemplateFunc(icons)
+
+	tests := map[string]string{
+		"di:glance":                      "https://cdn.jsdelivr.net/gh/homarr-labs/dashboard-icons/svg/glance.svg",
+		"di:glance.png":                  "https://cdn.jsdelivr.net/gh/homarr-labs/dashboard-icons/png/glance.png",
+		"mdi:home-outline":               "https://cdn.jsdelivr.net/npm/@mdi/svg@latest/svg/home-outline.svg",
+		"mirror:nas.webp":                "https://icons.example.org/nas.webp",
+		"mirror:c++@2x.png":              "https://icons.example.org/c++@2x.png",
+		"unknown:glance":                 "https://cdn.jsdelivr.net/gh/homarr-labs/dashboard-icons/svg/kubernetes.svg",
+		"https://example.org/glance.png": "https://example.org/glance.png",
+		"http://localhost/glance":        "http://localhost/glance",
+		"data:image/png;base64,iVBORw0K": "data:image/png;base64,iVBORw0K",
+	}
+
+	for input, want := range tests {
+		if got := string(icon(input)); got != want {
+			t.Errorf("icon(%q) = %q, want %q", input, got, want)
+		}
+	}
+
+	icons.publicUrl = "https://glance-k8s.example.org"
+	if got, want := string(icon("mdi:home")), "https://glance-k8s.example.org/icons/mdi/home.svg"; got != want {
+		t.Errorf("proxied icon = %q, want %q", got, want)
+	}
+
+	if got, want := string(icon("mirror:c++@2x.png")), "https://glance-k8s.example.org/icons/mirror/c++@2x.png"; got != want {
+		t.Errorf("proxied icon = %q, want %q", got, want)
+	}
+}
@@ -3,6 +3,7 @@ package extension
 import (
 	"log/slog"
 	"net/http"
+	"os"
 
 	"github.com/labstack/echo/v5"
 	"github.com/labstack/echo/v5/middleware"
@@ -10,15 +11,23 @@ import (
 	"github.com/lukasdietrich/glance-k8s/internal/k8s"
 )
 
-func New(cluster *k8s.Cluster) http.Handler {
+func New(cluster *k8s.Cluster) (http.Handler, error) {
+	shorthands, err := loadIconShorthands(os.Getenv("GLANCE_ICON_SHORTHANDS"))
+	if err != nil {
+		return nil, err
+	}
+
+	icons := newIconProxy(shorthands)
+
 	r := echo.New()
-	r.Renderer = mustTemplates()
+	r.Renderer = mustTemplates(icons)
 
 	r.Use(logError())
 	r.Use(middleware.Recover())
 	r.Use(middleware.Gzip())
 
 	r.GET("/healthz", health())
+	r.GET("/icons/:shorthand/:file", icons.handler())
 
 	e := r.Group("/extension")
 
@@ -27,8 +36,14 @@ func New(cluster *k8s.Cluster) http.Handler {
 
 	e.GET("/nodes", nodes(cluster), widgetTitle("Kubernetes Nodes"))
 	e.GET("/apps", apps(cluster), widgetTitle("Kubernetes Apps"))
+	e.GET("/history", history(cluster), widgetTitle("Kubernetes History"))
+	e.GET("/top", top(cluster), widgetTitle("Kubernetes Top"))
+	e.GET("/events", events(cluster), widgetTitle("Kubernetes Events"))
+	e.GET("/storage", storage(cluster), widgetTitle("Kubernetes Storage"))
+	e.GET("/certificates", certificates(cluster), widgetTitle("Kubernetes Certificates"))
+	e.GET("/helm", helm(cluster), widgetTitle("Helm Releases"))
 
-	return r
+	return r, nil
 }
 
 func logError() echo.MiddlewareFunc {
@@ -4,7 +4,9 @@ import (
 	"embed"
 	"fmt"
 	"html/template"
+	"log/slog"
 	"regexp"
+	"strings"
 
 	"github.com/Masterminds/sprig/v3"
 	"github.com/labstack/echo/v5"
@@ -14,12 +16,12 @@ import (
 //go:embed templates
 var templatesFs embed.FS
 
-func mustTemplates() echo.Renderer {
+func mustTemplates(icons *iconProxy) echo.Renderer {
 	return &echo.TemplateRenderer{
 		Template: template.Must(
 			template.New("").
 				Funcs(sprig.FuncMap()).
-				Funcs(templateFuncs()).
+				Funcs(templateFuncs(icons)).
 				ParseFS(templatesFs,
 					"*/*.html",
 					"*/*/*.html",
@@ -28,11 +30,13 @@ func mustTemplates() echo.Renderer {
 	}
 }
 
-func templateFuncs() template.FuncMap {
+func templateFuncs(icons *iconProxy) template.FuncMap {
 	return template.FuncMap{
 		"url":                    urlTemplateFunc(),
-		"icon":                   iconTemplateFunc(),
+		"icon":                   iconTemplateFunc(icons),
 		"formatResourceQuantity": formatResourceQuantityTemplateFunc(),
+		"formatCpuQuantity":      formatCpuQuantityTemplateFunc(),
+		"formatStorageQuantity":  formatStorageQuantityTemplateFunc(),
 	}
 }
 
@@ -42,36 +46,41 @@ func urlTemplateFunc() func(string) template.URL {
 	}
 }
 
-func iconTemplateFunc() func(string) template.URL {
-	pattern := regexp.MustCompile(`^((?P<shorthand>(di|si)):)(?P<name>[^\s\.]*)(\.(?P<extension>\w*))?$`)
+func iconTemplateFunc(icons *iconProxy) func(string) template.URL {
+	pattern := regexp.MustCompile(`^(?P<shorthand>[a-z][\w-]*):(?P<name>[^\s./][^\s.]*)(\.(?P<extension>\w+))?$`)
 
 	return func(s string) template.URL {
-		if match := pattern.FindStringSubmatch(s); match != nil {
-			var (
-				shorthand = match[pattern.SubexpIndex("shorthand")]
-				name      = match[pattern.SubexpIndex("name")]
-				extension = match[pattern.SubexpIndex("extension")]
-			)
-
-			return template.URL(resolveShorthandIcon(shorthand, name, extension))
+		match := pattern.FindStringSubmatch(s)
+		if match == nil {
+			return template.URL(s)
 		}
 
-		return template.URL(s)
-	}
-}
+		var (
+			shorthand = match[pattern.SubexpIndex("shorthand")]
+			name      = match[pattern.SubexpIndex("name")]
+			extension = match[pattern.SubexpIndex("extension")]
+		)
 
-func resolveShorthandIcon(shorthand, name, extension string) string {
-	if extension == "" {
-		extension = "svg"
-	}
+		if _, ok := icons.shorthands[shorthand]; !ok {
+			if strings.ContainsAny(name, "/;") {
+				// Not a shorthand, but a url with a scheme, e.g. data:image/png;base64
+				return template.URL(s)
+			}
 
-	switch shorthand {
-	case "si":
-		return fmt.Sprintf("https://cdn.jsdelivr.net/npm/simple-icons@latest/icons/%[1]s.svg", name)
-	case "di":
-		return fmt.Sprintf("https://cdn.jsdelivr.net/gh/homarr-labs/dashboard-icons/%[2]s/%[1]s.%[2]s", name, extension)
-	default:
-		return fmt.Sprintf("unknown icon shorthand %q", shorthand)
+			slog.Debug("unknown icon shorthand, using fallback", slog.String("icon", s))
+			shorthand, name, extension = fallbackIconShorthand, fallbackIconName, ""
+		}
+
+		if extension == "" {
+			extension = "svg"
+		}
+
+		if url, ok := icons.proxyUrl(shorthand, name, extension); ok {
+			return template.URL(url)
+		}
+
+		url, _ := icons.shorthands.resolve(shorthand, name, extension)
+		return template.URL(url)
 	}
 }
 
@@ -81,3 +90,22 @@ func formatResourceQuantityTemplateFunc() func(*resource.Quantity) template.HTML
 		return template.HTML(fmt.Sprintf(`%d <span class="color-base size-h5">Mi</span>`, scaled))
 	}
 }
+
+func formatCpuQuantityTemplateFunc() func(*resource.Quantity) template.HTML {
+	return func(quantity *resource.Quantity) template.HTML {
+		return template.HTML(fmt.Sprintf(`%d <span class="color-base size-h5">m</span>`, quantity.MilliValue()))
+	}
+}
+
+func formatStorageQuantityTemplateFunc() func(*resource.Quantity) template.HTML {
+	const gibibyte = 1 << 30
+
+	return func(quantity *resource.Quantity) template.HTML {
+		bytes := quantity.Value()
+		if bytes < gibibyte {
+			return template.HTML(fmt.Sprintf(`%d <span class="color-base size-h5">Mi</span>`, bytes>>20))
+		}
+
+		return template.HTML(fmt.Sprintf(`%.1f <span class="color-base size-h5">Gi</span>`, float64(bytes)/gibibyte))
+	}
+}
@@ -1,6 +1,7 @@
 package extension
 
 import (
+	"cmp"
 	"net/http"
 
 	"github.com/labstack/echo/v5"
@@ -8,6 +9,68 @@ import (
 	"github.com/lukasdietrich/glance-k8s/internal/k8s"
 )
 
+type appsRequest struct {
+	HidePattern     []string `query:"hide-pattern"`
+	ShowIf          []string `query:"show-if"`
+	AutoGroup       bool     `query:"auto-group"`
+	NameExpr        string   `query:"name-expr"`
+	DescriptionExpr string   `query:"description-expr"`
+	IconExpr        string   `query:"icon-expr"`
+	UrlExpr         string   `query:"url-expr"`
+	SortBy          string   `query:"sort-by"`
+	GroupBy         string   `query:"group-by"`
+	Limit           int      `query:"limit"`
+	CollapseAfter   int      `query:"collapse-after"`
+}
+
+type topRequest struct {
+	By        string   `query:"by"`
+	Of        string   `query:"of"`
+	Limit     int      `query:"limit"`
+	Namespace []string `query:"namespace"`
+	ShowIf    []string `query:"show-if"`
+}
+
+type eventsRequest struct {
+	Namespace []string `query:"namespace"`
+	Reason    []string `query:"reason"`
+	ShowIf    []string `query:"show-if"`
+	Limit     int      `query:"limit"`
+	MaxAge    string   `query:"max-age"`
+}
+
+type storageRequest struct {
+	Namespace    []string `query:"namespace"`
+	StorageClass []string `query:"storage-class"`
+	Threshold    int      `query:"threshold"`
+}
+
+type certificatesRequest struct {
+	Namespace []string `query:"namespace"`
+	Limit     int      `query:"limit"`
+	Warning   int      `query:"warning"`
+	Critical  int      `query:"critical"`
+}
+
+type helmRequest struct {
+	Namespace  []string `query:"namespace"`
+	StuckAfter string   `query:"stuck-after"`
+}
+
+// validateLimit rejects negative limits, which would otherwise be taken as
+// a slice bound.
+func validateLimit(limit int) error {
+	return validateNotNegative("limit", limit)
+}
+
+func validateNotNegative(name string, value int) error {
+	if value < 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, name+" must not be negative")
+	}
+
+	return nil
+}
+
 func nodes(cluster *k8s.Cluster) echo.HandlerFunc {
 	return func(ctx *echo.Context) error {
 		nodes, err := cluster.Nodes(ctx.Request().Context())
@@ -21,11 +84,25 @@ func nodes(cluster *k8s.Cluster) echo.HandlerFunc {
 
 func apps(cluster *k8s.Cluster) echo.HandlerFunc {
 	return func(ctx *echo.Context) error {
-		var req struct {
-			HidePattern []string `query:"hide-pattern"`
-			ShowIf      []string `query:"show-if"`
+		var req appsRequest
+
+		if err := ctx.Bind(&req); err != nil {
+			return err
+		}
+
+		groups, err := cluster.AppGroups(ctx.Request().Context(), k8s.AppsOptions(req))
+		if err != nil {
+			return err
 		}
 
+		return ctx.Render(http.StatusOK, "widgets/apps", groups)
+	}
+}
+
+func history(cluster *k8s.Cluster) echo.HandlerFunc {
+	return func(ctx *echo.Context) error {
+		var req appsRequest
+
 		if err := ctx.Bind(&req); err != nil {
 			return err
 		}
@@ -35,6 +112,107 @@ func apps(cluster *k8s.Cluster) echo.HandlerFunc {
 			return err
 		}
 
-		return ctx.Render(http.StatusOK, "widgets/apps", apps)
+		return ctx.Render(http.StatusOK, "widgets/history", apps)
+	}
+}
+
+func top(cluster *k8s.Cluster) echo.HandlerFunc {
+	return func(ctx *echo.Context) error {
+		var req topRequest
+
+		if err := ctx.Bind(&req); err != nil {
+			return err
+		}
+
+		if err := validateLimit(req.Limit); err != nil {
+			return err
+		}
+
+		entries, err := cluster.Top(ctx.Request().Context(), k8s.TopOptions(req))
+		if err != nil {
+			return err
+		}
+
+		return ctx.Render(http.StatusOK, "widgets/top", entries)
+	}
+}
+
+func events(cluster *k8s.Cluster) echo.HandlerFunc {
+	return func(ctx *echo.Context) error {
+		var req eventsRequest
+
+		if err := ctx.Bind(&req); err != nil {
+			return err
+		}
+
+		if err := validateLimit(req.Limit); err != nil {
+			return err
+		}
+
+		groups, err := cluster.Events(ctx.Request().Context(), k8s.EventsOptions(req))
+		if err != nil {
+			return err
+		}
+
+		return ctx.Render(http.StatusOK, "widgets/events", groups)
+	}
+}
+
+func storage(cluster *k8s.Cluster) echo.HandlerFunc {
+	return func(ctx *echo.Context) error {
+		var req storageRequest
+
+		if err := ctx.Bind(&req); err != nil {
+			return err
+		}
+
+		volumes, err := cluster.Volumes(ctx.Request().Context(), k8s.StorageOptions(req))
+		if err != nil {
+			return err
+		}
+
+		return ctx.Render(http.StatusOK, "widgets/storage", volumes)
+	}
+}
+
+func certificates(cluster *k8s.Cluster) echo.HandlerFunc {
+	return func(ctx *echo.Context) error {
+		var req certificatesRequest
+
+		if err := ctx.Bind(&req); err != nil {
+			return err
+		}
+
+		if err := cmp.Or(
+			validateLimit(req.Limit),
+			validateNotNegative("warning", req.Warning),
+			validateNotNegative("critical", req.Critical),
+		); err != nil {
+			return err
+		}
+
+		certificates, err := cluster.Certificates(ctx.Request().Context(), k8s.CertificatesOptions(req))
+		if err != nil {
+			return err
+		}
+
+		return ctx.Render(http.StatusOK, "widgets/certificates", certificates)
+	}
+}
+
+func helm(cluster *k8s.Cluster) echo.HandlerFunc {
+	return func(ctx *echo.Context) error {
+		var req helmRequest
+
+		if err := ctx.Bind(&req); err != nil {
+			return err
+		}
+
+		releases, err := cluster.HelmReleases(ctx.Request().Context(), k8s.HelmOptions(req))
+		if err != nil {
+			return err
+		}
+
+		return ctx.Render(http.StatusOK, "widgets/helm", releases)
 	}
 }
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var certificateResource = schema.GroupVersionResource{
+	Group:    "cert-manager.io",
+	Version:  "v1",
+	Resource: "certificates",
+}
+
+// Certificate is the subset of a cert-manager Certificate needed to display
+// its expiry. cert-manager is read using the dynamic client, so it does not
+// become a dependency.
+type Certificate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec struct {
+		SecretName string   `json:"secretName"`
+		DNSNames   []string `json:"dnsNames"`
+	} `json:"spec"`
+
+	Status struct {
+		NotAfter *metav1.Time `json:"notAfter"`
+	} `json:"status"`
+}
+
+// Certificates returns all cert-manager Certificates, or none if cert-manager
+// is not installed.
+func (c *Client) Certificates(ctx context.Context) ([]Certificate, error) {
+	certificates, err := fetchContinue(ctx,
+		func(ctx context.Context, opts listOptions) ([]Certificate, string, error) {
+			certificateList, err := c.dynamic.Resource(certificateResource).List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+
+			certificates := make([]Certificate, len(certificateList.Items))
+			for i, item := range certificateList.Items {
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &certificates[i]); err != nil {
+					return nil, "", err
+				}
+			}
+
+			return certificates, certificateList.GetContinue(), nil
+		})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+
+	return certificates, err
+}
+
+// TLSSecrets returns all secrets of type kubernetes.io/tls. Private keys are
+// removed, so they are never kept in memory longer than necessary.
+func (c *Client) TLSSecrets(ctx context.Context) ([]Secret, error) {
+	secrets, err := fetchContinue(ctx,
+		func(ctx context.Context, opts listOptions) ([]Secret, string, error) {
+			opts.FieldSelector = "type=" + string(SecretTypeTLS)
+
+			secretList, err := c.kube.CoreV1().Secrets("").List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+
+			return secretList.Items, secretList.Continue, nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, secret := range secrets {
+		delete(secret.Data, TLSPrivateKeyKey)
+	}
+
+	return secrets, nil
+}
@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"os"
 
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -17,6 +18,7 @@ type Client struct {
 	kube    *kubernetes.Clientset
 	metrics *metricsv.Clientset
 	gateway *gatewayv.Clientset
+	dynamic *dynamic.DynamicClient
 }
 
 func Connect() (*Client, error) {
@@ -40,10 +42,16 @@ func Connect() (*Client, error) {
 		return nil, fmt.Errorf("could not create gatewayClientset client: %w", err)
 	}
 
+	dynamic, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("could not create dynamic client: %w", err)
+	}
+
 	return &Client{
 		kube:    kube,
 		metrics: metrics,
 		gateway: gateway,
+		dynamic: dynamic,
 	}, nil
 }
 
@@ -0,0 +1,17 @@
+package api
+
+import (
+	"context"
+)
+
+func (c *Client) EndpointSlices(ctx context.Context) ([]EndpointSlice, error) {
+	return fetchContinue(ctx,
+		func(ctx context.Context, opts listOptions) ([]EndpointSlice, string, error) {
+			endpointSliceList, err := c.kube.DiscoveryV1().EndpointSlices("").List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+
+			return endpointSliceList.Items, endpointSliceList.Continue, nil
+		})
+}
@@ -0,0 +1,20 @@
+package api
+
+import (
+	"context"
+)
+
+// WarningEvents returns all events of type Warning.
+func (c *Client) WarningEvents(ctx context.Context) ([]Event, error) {
+	return fetchContinue(ctx,
+		func(ctx context.Context, opts listOptions) ([]Event, string, error) {
+			opts.FieldSelector = "type=" + EventTypeWarning
+
+			eventList, err := c.kube.EventsV1().Events("").List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+
+			return eventList.Items, eventList.Continue, nil
+		})
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"context"
+)
+
+// HelmReleaseSecrets returns the secrets Helm stores the revisions of a
+// release in. Superseded revisions are skipped, as only the latest revision
+// of a release is of interest.
+func (c *Client) HelmReleaseSecrets(ctx context.Context) ([]Secret, error) {
+	return fetchContinue(ctx,
+		func(ctx context.Context, opts listOptions) ([]Secret, string, error) {
+			opts.FieldSelector = "type=" + SecretTypeHelmRelease
+			opts.LabelSelector = "owner=helm,status!=superseded"
+
+			secretList, err := c.kube.CoreV1().Secrets("").List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+
+			return secretList.Items, secretList.Continue, nil
+		})
+}
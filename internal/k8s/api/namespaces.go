@@ -0,0 +1,17 @@
+package api
+
+import (
+	"context"
+)
+
+func (c *Client) Namespaces(ctx context.Context) ([]Namespace, error) {
+	return fetchContinue(ctx,
+		func(ctx context.Context, opts listOptions) ([]Namespace, string, error) {
+			namespaceList, err := c.kube.CoreV1().Namespaces().List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+
+			return namespaceList.Items, namespaceList.Continue, nil
+		})
+}
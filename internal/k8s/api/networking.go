@@ -37,3 +37,14 @@ func (c *Client) HTTPRoutes(ctx context.Context) ([]HTTPRoute, error) {
 			return httpRoutes.Items, httpRoutes.Continue, nil
 		})
 }
+
+func (c *Client) Gateways(ctx context.Context) ([]Gateway, error) {
+	return fetchContinue(ctx,
+		func(ctx context.Context, opts listOptions) ([]Gateway, string, error) {
+			gateways, err := c.gateway.GatewayV1().Gateways("").List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+			return gateways.Items, gateways.Continue, nil
+		})
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"context"
+)
+
+func (c *Client) Pods(ctx context.Context) ([]Pod, error) {
+	return fetchContinue(ctx,
+		func(ctx context.Context, opts listOptions) ([]Pod, string, error) {
+			podList, err := c.kube.CoreV1().Pods("").List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+
+			return podList.Items, podList.Continue, nil
+		})
+}
+
+func (c *Client) PodMetrics(ctx context.Context) ([]PodMetrics, error) {
+	return fetchContinue(ctx,
+		func(ctx context.Context, opts listOptions) ([]PodMetrics, string, error) {
+			metricsList, err := c.metrics.MetricsV1beta1().PodMetricses("").List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+
+			return metricsList.Items, metricsList.Continue, nil
+		})
+}
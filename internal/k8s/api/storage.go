@@ -0,0 +1,125 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+func (c *Client) PersistentVolumeClaims(ctx context.Context) ([]PersistentVolumeClaim, error) {
+	return fetchContinue(ctx,
+		func(ctx context.Context, opts listOptions) ([]PersistentVolumeClaim, string, error) {
+			claimList, err := c.kube.CoreV1().PersistentVolumeClaims("").List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+
+			return claimList.Items, claimList.Continue, nil
+		})
+}
+
+// VolumeStats returns the usage of all volumes backed by a persistent volume
+// claim, as reported by the kubelet summary API of every node. Nodes that
+// cannot be reached are skipped, unless access to the node proxy is forbidden.
+// A claim mounted by several pods is reported once per pod.
+func (c *Client) VolumeStats(ctx context.Context) ([]VolumeStats, error) {
+	nodes, err := c.Nodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		stats     []VolumeStats
+		forbidden error
+	)
+
+	for _, node := range nodes {
+		wg.Go(func() {
+			summary, err := c.nodeSummary(ctx, node.Name)
+			if apierrors.IsForbidden(err) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				forbidden = err
+				return
+			}
+
+			if err != nil {
+				slog.Warn("could not fetch volume stats",
+					slog.String("node", node.Name),
+					slog.Any("err", err),
+				)
+
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			for _, pod := range summary.Pods {
+				for _, volume := range pod.Volumes {
+					if volume.PVCRef != nil {
+						stats = append(stats, volume)
+					}
+				}
+			}
+		})
+	}
+
+	wg.Wait()
+
+	if forbidden != nil {
+		return nil, forbidden
+	}
+
+	return stats, nil
+}
+
+func (c *Client) nodeSummary(ctx context.Context, name string) (*nodeSummary, error) {
+	content, err := c.kube.CoreV1().RESTClient().
+		Get().
+		Resource("nodes").
+		Name(name).
+		SubResource("proxy").
+		Suffix("stats/summary").
+		DoRaw(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var summary nodeSummary
+	if err := json.Unmarshal(content, &summary); err != nil {
+		return nil, fmt.Errorf("could not parse summary: %w", err)
+	}
+
+	return &summary, nil
+}
+
+// nodeSummary is the subset of the kubelet summary API needed for volume
+// stats. See k8s.io/kubelet/pkg/apis/stats/v1alpha1 for the full schema.
+type nodeSummary struct {
+	Pods []struct {
+		Volumes []VolumeStats `json:"volume"`
+	} `json:"pods"`
+}
+
+// VolumeStats is the filesystem usage of a single pod volume.
+type VolumeStats struct {
+	Name           string        `json:"name"`
+	PVCRef         *PVCReference `json:"pvcRef"`
+	CapacityBytes  *uint64       `json:"capacityBytes"`
+	UsedBytes      *uint64       `json:"usedBytes"`
+	AvailableBytes *uint64       `json:"availableBytes"`
+}
+
+// PVCReference references the claim backing a volume.
+type PVCReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
@@ -3,7 +3,10 @@ package api
 import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	eventsv1 "k8s.io/api/events/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
 	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
@@ -16,16 +19,57 @@ const (
 type ObjectMeta = metav1.ObjectMeta
 type listOptions = metav1.ListOptions
 
+type Namespace = corev1.Namespace
+
 type Node = corev1.Node
 type NodeCondition = corev1.NodeCondition
 type NodeConditionType = corev1.NodeConditionType
 type NodeStatus = corev1.NodeStatus
 type NodeMetrics = metricsv1beta1.NodeMetrics
 
+type Pod = corev1.Pod
+type PodMetrics = metricsv1beta1.PodMetrics
+type ResourceList = corev1.ResourceList
+type ResourceName = corev1.ResourceName
+type Quantity = resource.Quantity
+
+const (
+	ResourceCPU    = corev1.ResourceCPU
+	ResourceMemory = corev1.ResourceMemory
+)
+
+type PersistentVolumeClaim = corev1.PersistentVolumeClaim
+
+const (
+	ClaimBound      = corev1.ClaimBound
+	ResourceStorage = corev1.ResourceStorage
+)
+
+type Secret = corev1.Secret
+
+const (
+	SecretTypeTLS    = corev1.SecretTypeTLS
+	TLSCertKey       = corev1.TLSCertKey
+	TLSPrivateKeyKey = corev1.TLSPrivateKeyKey
+)
+
+const SecretTypeHelmRelease = "helm.sh/release.v1"
+
+type Event = eventsv1.Event
+
+const EventTypeWarning = corev1.EventTypeWarning
+
 type Ingress = networkingv1.Ingress
 type HTTPIngressPath = networkingv1.HTTPIngressPath
 type Service = corev1.Service
 type HTTPRoute = gatewayapiv1.HTTPRoute
+type Gateway = gatewayapiv1.Gateway
+type EndpointSlice = discoveryv1.EndpointSlice
+
+const (
+	ServiceTypeExternalName = corev1.ServiceTypeExternalName
+	LabelServiceName        = discoveryv1.LabelServiceName
+)
 
 type Deployment = appsv1.Deployment
 type DeploymentSpec = appsv1.DeploymentSpec
@@ -38,3 +82,4 @@ type DaemonSetSpec = appsv1.DaemonSetSpec
 
 type LabelSelector = metav1.LabelSelector
 type PodTemplateSpec = corev1.PodTemplateSpec
+type Container = corev1.Container
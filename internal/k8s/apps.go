@@ -2,15 +2,17 @@ package k8s
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/url"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
-	"github.com/expr-lang/expr"
-	"github.com/expr-lang/expr/vm"
 	"github.com/samber/lo"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -26,6 +28,17 @@ const (
 	aDescription = "glance/description"
 	aId          = "glance/id"
 	aParent      = "glance/parent"
+
+	aMaintenance      = "glance/maintenance"
+	aMaintenanceUntil = "glance/maintenance-until"
+)
+
+const (
+	lPartOf    = "app.kubernetes.io/part-of"
+	lInstance  = "app.kubernetes.io/instance"
+	lComponent = "app.kubernetes.io/component"
+	lHelmChart = "helm.sh/chart"
+	lVersion   = "app.kubernetes.io/version"
 )
 
 type AppSlice []*App
@@ -44,14 +57,47 @@ func (a AppSlice) Swap(i int, j int) {
 }
 
 type App struct {
+	Namespace    *api.Namespace
 	Annotations  map[string]string
 	Ingress      *api.Ingress
 	HTTPRoute    *api.HTTPRoute
+	Services     []api.Service
 	Workload     Workload
 	Dependencies WorkloadSlice
+	Warnings     []string
+	Links        []Link
+	Probe        *ProbeResult
+	History      *History
+	Resources    *Resources
+
+	imageIcon    string
+	display      appDisplay
+	maintenances []Maintenance
+}
+
+// key uniquely identifies an app across requests. Static and route apps are
+// prefixed, so they never share a key with a workload in the probes or the
+// history.
+func (a *App) key() string {
+	switch workload := a.Workload.(type) {
+	case staticWorkload:
+		return "static/" + resourceFullname(workload)
+	case *routeWorkload:
+		return workload.key()
+	default:
+		return resourceFullname(workload)
+	}
+}
+
+func (a *App) subjectKey() string {
+	return "apps/" + a.key()
 }
 
 func (a *App) Name() string {
+	if a.display.name != "" {
+		return a.display.name
+	}
+
 	if title, ok := a.Annotations[aName]; ok {
 		return title
 	}
@@ -60,14 +106,26 @@ func (a *App) Name() string {
 }
 
 func (a *App) Icon() string {
+	if a.display.icon != "" {
+		return a.display.icon
+	}
+
 	if icon, ok := a.Annotations[aIcon]; ok {
 		return icon
 	}
 
+	if a.imageIcon != "" {
+		return a.imageIcon
+	}
+
 	return "di:kubernetes"
 }
 
 func (a *App) Url() string {
+	if a.display.url != "" {
+		return a.display.url
+	}
+
 	if url, ok := a.Annotations[aUrl]; ok {
 		return url
 	}
@@ -160,14 +218,88 @@ func (a *App) SameTab() bool {
 }
 
 func (a *App) Description() string {
+	if a.display.description != "" {
+		return a.display.description
+	}
+
 	return a.Annotations[aDescription]
 }
 
+// Maintenance describes an intentional downtime of an app.
+type Maintenance struct {
+	Reason string
+	Until  time.Time
+}
+
+// Maintenance returns the active maintenance of the app, annotated on the app
+// itself or its namespace, or nil.
+func (a *App) Maintenance() *Maintenance {
+	now := time.Now()
+
+	for _, maintenance := range a.maintenances {
+		if maintenance.Until.IsZero() || !now.After(maintenance.Until) {
+			return &maintenance
+		}
+	}
+
+	return nil
+}
+
+// parseMaintenances returns the maintenances annotated on the app and its
+// namespace, in order of precedence. An explicit "false" on the app opts out
+// of a maintenance of its namespace. An invalid expiry is reported, but the
+// maintenance does not expire.
+func parseMaintenances(app *App) ([]Maintenance, error) {
+	sources := []map[string]string{app.Annotations}
+	if app.Namespace != nil {
+		sources = append(sources, app.Namespace.GetAnnotations())
+	}
+
+	var (
+		maintenances []Maintenance
+		errs         []error
+	)
+
+	for _, annotations := range sources {
+		reason, ok := annotations[aMaintenance]
+		if !ok {
+			continue
+		}
+
+		if reason == "false" {
+			break
+		}
+
+		if reason == "true" {
+			reason = ""
+		}
+
+		maintenance := Maintenance{Reason: reason}
+
+		if value, ok := annotations[aMaintenanceUntil]; ok {
+			until, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid maintenance expiry %q: %w", value, err))
+			} else {
+				maintenance.Until = until
+			}
+		}
+
+		maintenances = append(maintenances, maintenance)
+	}
+
+	return maintenances, errors.Join(errs...)
+}
+
 func (a *App) Ready() bool {
 	if !a.Workload.GetStatus().Ready() {
 		return false
 	}
 
+	if a.Probe != nil && !a.Probe.Ok {
+		return false
+	}
+
 	for _, dependency := range a.Dependencies {
 		if !dependency.GetStatus().Ready() {
 			return false
@@ -178,8 +310,17 @@ func (a *App) Ready() bool {
 }
 
 type AppsOptions struct {
-	HidePattern []string
-	ShowIf      []string
+	HidePattern     []string
+	ShowIf          []string
+	AutoGroup       bool
+	NameExpr        string
+	DescriptionExpr string
+	IconExpr        string
+	UrlExpr         string
+	SortBy          string
+	GroupBy         string
+	Limit           int
+	CollapseAfter   int
 }
 
 func (c *Cluster) Apps(ctx context.Context, opts AppsOptions) (AppSlice, error) {
@@ -203,9 +344,21 @@ func (c *Cluster) Apps(ctx context.Context, opts AppsOptions) (AppSlice, error)
 		slog.Warn("could not fetch httpRoutes", slog.Any("err", err))
 	}
 
+	namespaces, err := c.client.Namespaces(ctx)
+	if err != nil {
+		slog.Warn("could not fetch namespaces", slog.Any("err", err))
+	}
+
+	podMetrics, err := c.client.PodMetrics(ctx)
+	c.podMetricsState.observe("pod metrics", err)
+
 	findIngress := makeIngressFinder(workloads, services, ingresses, httpRoutes)
+	findPodMetrics := makePodMetricsFinder(podMetrics)
+	namespacesByName := lo.SliceToMap(namespaces, func(namespace api.Namespace) (string, api.Namespace) {
+		return namespace.Name, namespace
+	})
 
-	apps := groupApps(workloads)
+	apps := groupApps(workloads, opts.AutoGroup, findIngress)
 	for _, app := range apps {
 		sort.Stable(app.Dependencies)
 
@@ -221,27 +374,127 @@ func (c *Cluster) Apps(ctx context.Context, opts AppsOptions) (AppSlice, error)
 			)
 		}
 
-		app.Annotations = app.Workload.GetAnnotations()
+		appWorkloads := append(WorkloadSlice{app.Workload}, app.Dependencies...)
+		app.Services = findServices(services, appWorkloads...)
+
+		if icon, ok := c.imageIcons.forWorkload(app.Workload); ok {
+			app.imageIcon = icon
+		}
+
+		if namespace, ok := namespacesByName[app.Workload.GetNamespace()]; ok {
+			app.Namespace = &namespace
+		}
+
+		app.Resources = appResources(appWorkloads, findPodMetrics)
 
-		if ingress, httpRoute, ok := findIngress(append(WorkloadSlice{app.Workload}, app.Dependencies...)...); ok {
+		if ingress, httpRoute, ok := findIngress(appWorkloads...); ok {
 			app.Ingress = ingress
 			app.HTTPRoute = httpRoute
+		}
+
+		app.Annotations = mergeAnnotations(app)
+	}
+
+	if c.routeApps {
+		readiness := serviceReadiness(nil)
+
+		endpointSlices, err := c.client.EndpointSlices(ctx)
+		if err != nil {
+			slog.Warn("could not fetch endpoint slices, readiness of route apps is unknown", slog.Any("err", err))
+		} else {
+			readiness = newServiceReadiness(services, endpointSlices)
+		}
 
-			if ingress != nil {
-				app.Annotations = lo.Assign(app.Annotations, ingress.GetAnnotations())
+		for _, app := range buildRouteApps(apps, ingresses, httpRoutes, readiness) {
+			app.Annotations = app.Workload.GetAnnotations()
+
+			if readiness == nil {
+				app.Warnings = append(app.Warnings, "readiness is unknown, because endpoint slices could not be fetched")
+			}
+
+			if namespace, ok := namespacesByName[app.Workload.GetNamespace()]; ok {
+				app.Namespace = &namespace
 			}
-			if httpRoute != nil {
-				app.Annotations = lo.Assign(app.Annotations, httpRoute.GetAnnotations())
+
+			apps = append(apps, app)
+		}
+	}
+
+	for _, workload := range c.staticApps {
+		app := &App{Workload: workload, Annotations: workload.GetAnnotations()}
+
+		if namespace, ok := namespacesByName[workload.GetNamespace()]; ok {
+			app.Namespace = &namespace
+		}
+
+		apps = append(apps, app)
+	}
+
+	for _, app := range apps {
+		links, err := parseLinks(app.Annotations)
+		if err != nil {
+			app.Warnings = append(app.Warnings, err.Error())
+		}
+
+		app.Links = links
+
+		maintenances, err := parseMaintenances(app)
+		if err != nil {
+			slog.Warn("could not parse maintenance", slog.String("app", app.key()), slog.Any("err", err))
+			app.Warnings = append(app.Warnings, err.Error())
+		}
+
+		app.maintenances = maintenances
+	}
+
+	if c.prober != nil {
+		for _, app := range apps {
+			if result, ok := c.prober.result(app.key()); ok {
+				app.Probe = result
 			}
 		}
 	}
 
+	if c.history != nil {
+		now := time.Now()
+		for _, app := range apps {
+			app.History, _ = c.history.summary(app.subjectKey(), now)
+		}
+	}
+
+	if err := applyDisplayExpressions(apps, opts); err != nil {
+		return nil, fmt.Errorf("could not apply display expressions: %w", err)
+	}
+
 	if apps, err = filterApps(apps, opts); err != nil {
 		return nil, fmt.Errorf("could not filter apps: %w", err)
 	}
 
-	sort.Stable(apps)
-	return apps, nil
+	if err := sortApps(apps, opts.SortBy); err != nil {
+		return nil, fmt.Errorf("could not sort apps: %w", err)
+	}
+
+	if opts.Limit > 0 || opts.CollapseAfter > 0 {
+		pinUnhealthy(apps)
+	}
+
+	return limitApps(apps, opts.Limit), nil
+}
+
+// AppGroups returns the apps split into sections by opts.GroupBy.
+func (c *Cluster) AppGroups(ctx context.Context, opts AppsOptions) ([]AppGroup, error) {
+	apps, err := c.Apps(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := apps.GroupBy(opts.GroupBy)
+	if err != nil {
+		return nil, fmt.Errorf("could not group apps: %w", err)
+	}
+
+	collapseGroups(groups, opts.CollapseAfter)
+	return groups, nil
 }
 
 func filterApps(apps AppSlice, opts AppsOptions) (AppSlice, error) {
@@ -266,14 +519,20 @@ func filterApps(apps AppSlice, opts AppsOptions) (AppSlice, error) {
 	return lo.Filter(apps, filter), nil
 }
 
+// warnHidePatternDeprecated logs the deprecation of hide-pattern only once,
+// instead of on every request.
+var warnHidePatternDeprecated = sync.OnceFunc(func() {
+	slog.Warn("hide-pattern is deprecated, use show-if instead")
+})
+
 func buildHidePatternFilterFunc(patterns []string) (func(*App) bool, error) {
 	if len(patterns) > 0 {
-		slog.Warn("hide-pattern is deprecated, use show-if instead")
+		warnHidePatternDeprecated()
 	}
 
 	hidePatterns := make([]*regexp.Regexp, len(patterns))
 	for i, pattern := range patterns {
-		if re, err := regexp.Compile(pattern); err != nil {
+		if re, err := hidePatternCache.get(pattern); err != nil {
 			return nil, err
 		} else {
 			hidePatterns[i] = re
@@ -295,69 +554,61 @@ func buildHidePatternFilterFunc(patterns []string) (func(*App) bool, error) {
 }
 
 func buildShowIfFilterFunc(expressions []string) (func(*App) bool, error) {
-	programs := make([]*vm.Program, len(expressions))
-	for i, expression := range expressions {
-		program, err := expr.Compile(expression, expr.AsBool(), expr.WarnOnAny())
-		if err != nil {
-			return nil, err
-		}
-
-		programs[i] = program
+	programs, err := showIfPrograms(showIfCache, expressions)
+	if err != nil {
+		return nil, err
 	}
 
 	filterFunc := func(app *App) bool {
-		env := struct {
-			Name        string            `expr:"name"`
-			Namespace   string            `expr:"namespace"`
-			Annotations map[string]string `expr:"annotations"`
-		}{
-			Name:        app.Workload.GetName(),
-			Namespace:   app.Workload.GetNamespace(),
-			Annotations: app.Annotations,
-		}
-
-		for _, program := range programs {
-			output, err := expr.Run(program, &env)
-			if err != nil {
-				slog.Error("could not evaluate expression", slog.Any("err", err))
-				return false
-			}
-
-			if !output.(bool) {
-				return false
-			}
-		}
-
-		return true
+		env := newAppEnv(app)
+		return matchesShowIf(programs, &env)
 	}
 
 	return filterFunc, nil
 }
 
-func groupApps(workloads WorkloadSlice) AppSlice {
+func groupApps(workloads WorkloadSlice, autoGroup bool, findIngress ingressFinderFunc) AppSlice {
 	var apps AppSlice
 	mappedApps := make(map[string]*App)
+	labeledGroups := make(map[string]WorkloadSlice)
 
 	slog.Debug("grouping workloads",
 		slog.Int("workloads", len(workloads)),
+		slog.Bool("autoGroup", autoGroup),
 	)
 
 	for _, workload := range workloads {
 		annotations := workload.GetAnnotations()
 
 		if id, ok := annotations[aId]; ok {
+			id = scopedGroupId(workload, id)
+
 			slog.Debug("workload is parent of group",
 				slog.String("namespace", workload.GetNamespace()),
 				slog.String("name", workload.GetName()),
 				slog.String("id", id),
 			)
 
-			if app, ok := mappedApps[id]; ok {
+			if app, ok := mappedApps[id]; !ok {
+				mappedApps[id] = &App{Workload: workload}
+			} else if app.Workload == nil {
 				app.Workload = workload
 			} else {
-				mappedApps[id] = &App{Workload: workload}
+				warning := fmt.Sprintf("duplicate %s %q on %s and %s",
+					aId, id, resourceFullname(app.Workload), resourceFullname(workload))
+
+				slog.Warn("duplicate group id",
+					slog.String("id", id),
+					slog.String("workload", resourceFullname(app.Workload)),
+					slog.String("duplicate", resourceFullname(workload)),
+				)
+
+				app.Dependencies = append(app.Dependencies, workload)
+				app.Warnings = append(app.Warnings, warning)
 			}
 		} else if parent, ok := annotations[aParent]; ok {
+			parent = scopedGroupId(workload, parent)
+
 			slog.Debug("workload is dependency of group",
 				slog.String("namespace", workload.GetNamespace()),
 				slog.String("name", workload.GetName()),
@@ -369,6 +620,14 @@ func groupApps(workloads WorkloadSlice) AppSlice {
 			} else {
 				mappedApps[parent] = &App{Dependencies: WorkloadSlice{workload}}
 			}
+		} else if key, ok := labeledGroupKey(workload); autoGroup && ok {
+			slog.Debug("workload is part of a labeled group",
+				slog.String("namespace", workload.GetNamespace()),
+				slog.String("name", workload.GetName()),
+				slog.String("group", key),
+			)
+
+			labeledGroups[key] = append(labeledGroups[key], workload)
 		} else {
 			slog.Debug("workload is not part of a group",
 				slog.String("namespace", workload.GetNamespace()),
@@ -379,6 +638,13 @@ func groupApps(workloads WorkloadSlice) AppSlice {
 		}
 	}
 
+	for _, group := range labeledGroups {
+		main := pickMainWorkload(group, findIngress)
+		dependencies := lo.Without(group, main)
+
+		apps = append(apps, &App{Workload: main, Dependencies: dependencies})
+	}
+
 	apps = append(apps, lo.Values(mappedApps)...)
 	slog.Debug("grouped apps",
 		slog.Int("workloads", len(workloads)),
@@ -388,6 +654,58 @@ func groupApps(workloads WorkloadSlice) AppSlice {
 	return apps
 }
 
+// scopedGroupId qualifies a glance/id or glance/parent value with the
+// namespace of the workload, unless it already is of the form
+// "namespace/id" to explicitly group across namespaces.
+func scopedGroupId(workload Workload, id string) string {
+	if strings.Contains(id, "/") {
+		return id
+	}
+
+	return fmt.Sprintf("%s/%s", workload.GetNamespace(), id)
+}
+
+// labeledGroupKey derives a grouping key from the recommended labels of a
+// workload. The first label found in order part-of, instance and
+// helm.sh/chart wins. Keys are always scoped to the namespace.
+func labeledGroupKey(workload Workload) (string, bool) {
+	labels := workload.GetLabels()
+
+	for _, label := range []string{lPartOf, lInstance, lHelmChart} {
+		if value := labels[label]; value != "" {
+			return fmt.Sprintf("%s/%s=%s", workload.GetNamespace(), label, value), true
+		}
+	}
+
+	return "", false
+}
+
+// pickMainWorkload guesses the main workload of a labeled group. Workloads
+// exposed by an Ingress or HTTPRoute are preferred, followed by workloads
+// with the "server" component label. Ties are broken by name.
+func pickMainWorkload(group WorkloadSlice, findIngress ingressFinderFunc) Workload {
+	score := func(workload Workload) int {
+		var score int
+
+		if _, _, ok := findIngress(workload); ok {
+			score += 2
+		}
+
+		if workload.GetLabels()[lComponent] == "server" {
+			score++
+		}
+
+		return score
+	}
+
+	sorted := append(WorkloadSlice(nil), group...)
+	sort.Stable(sorted)
+
+	return lo.MaxBy(sorted, func(a, b Workload) bool {
+		return score(a) > score(b)
+	})
+}
+
 type ingressFinderFunc func(workload ...Workload) (*api.Ingress, *api.HTTPRoute, bool)
 
 func makeIngressFinder(workloads WorkloadSlice, services []api.Service, ingresses []api.Ingress, httpRoutes []api.HTTPRoute) ingressFinderFunc {
@@ -503,6 +821,46 @@ func resourceFullname(resource interface {
 	return fmt.Sprintf("%s/%s", resource.GetNamespace(), resource.GetName())
 }
 
+// mergeAnnotations merges the annotations of all resources of an app by
+// precedence, from lowest to highest: workload, services, ingress and
+// httpRoute. Services of the main workload take precedence over services of
+// dependencies.
+func mergeAnnotations(app *App) map[string]string {
+	annotations := app.Workload.GetAnnotations()
+
+	for _, service := range slices.Backward(app.Services) {
+		annotations = lo.Assign(annotations, service.GetAnnotations())
+	}
+
+	if app.Ingress != nil {
+		annotations = lo.Assign(annotations, app.Ingress.GetAnnotations())
+	}
+
+	if app.HTTPRoute != nil {
+		annotations = lo.Assign(annotations, app.HTTPRoute.GetAnnotations())
+	}
+
+	return annotations
+}
+
+// findServices returns the services selecting any of the workloads, in the
+// order of the workloads.
+func findServices(services []api.Service, workloads ...Workload) []api.Service {
+	var found []api.Service
+
+	for _, workload := range workloads {
+		for _, service := range services {
+			if isServiceForWorkload(service, workload) && !slices.ContainsFunc(found, func(s api.Service) bool {
+				return resourceFullname(&s) == resourceFullname(&service)
+			}) {
+				found = append(found, service)
+			}
+		}
+	}
+
+	return found
+}
+
 func isServiceForWorkload(service api.Service, workload Workload) bool {
 	if service.GetNamespace() != workload.GetNamespace() {
 		return false
@@ -511,6 +869,12 @@ func isServiceForWorkload(service api.Service, workload Workload) bool {
 	labels := workload.GetSpec().Template.Labels
 	selector := service.Spec.Selector
 
+	// Services without a selector have manually managed endpoints, which
+	// do not belong to any workload.
+	if len(selector) == 0 {
+		return false
+	}
+
 	for k, v := range selector {
 		if labels[k] != v {
 			return false
@@ -0,0 +1,399 @@
+package k8s
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/lukasdietrich/glance-k8s/internal/k8s/api"
+)
+
+func testDeployment(namespace, name string, labels, annotations map[string]string) Workload {
+	return &deployment{api.Deployment{
+		ObjectMeta: api.ObjectMeta{
+			Namespace:   namespace,
+			Name:        name,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+	}}
+}
+
+func noIngress(...Workload) (*api.Ingress, *api.HTTPRoute, bool) {
+	return nil, nil, false
+}
+
+func findApp(apps AppSlice, fullname string) *App {
+	for _, app := range apps {
+		if resourceFullname(app.Workload) == fullname {
+			return app
+		}
+	}
+
+	return nil
+}
+
+func TestGroupApps_AutoGroupDisabled(t *testing.T) {
+	labels := map[string]string{lInstance: "immich"}
+	workloads := WorkloadSlice{
+		testDeployment("media", "immich-server", labels, nil),
+		testDeployment("media", "immich-ml", labels, nil),
+	}
+
+	if apps := groupApps(workloads, false, noIngress); len(apps) != 2 {
+		t.Fatalf("got %d apps, want 2", len(apps))
+	}
+}
+
+func TestGroupApps_AutoGroupByLabels(t *testing.T) {
+	workloads := WorkloadSlice{
+		testDeployment("media", "immich-ml", map[string]string{lInstance: "immich", lComponent: "machine-learning"}, nil),
+		testDeployment("media", "immich-server", map[string]string{lInstance: "immich", lComponent: "server"}, nil),
+		testDeployment("media", "immich-redis", map[string]string{lInstance: "immich"}, nil),
+		// Same instance label in another namespace must not be merged.
+		testDeployment("other", "immich-server", map[string]string{lInstance: "immich"}, nil),
+		testDeployment("media", "jellyfin", nil, nil),
+	}
+
+	apps := groupApps(workloads, true, noIngress)
+	if len(apps) != 3 {
+		t.Fatalf("got %d apps, want 3", len(apps))
+	}
+
+	app := findApp(apps, "media/immich-server")
+	if app == nil {
+		t.Fatalf("immich-server in media was not picked as main workload")
+	}
+
+	if len(app.Dependencies) != 2 {
+		t.Fatalf("got %d dependencies, want 2", len(app.Dependencies))
+	}
+}
+
+func TestGroupApps_AutoGroupPrefersIngress(t *testing.T) {
+	labels := map[string]string{lPartOf: "authentik"}
+	workloads := WorkloadSlice{
+		testDeployment("auth", "authentik-server", labels, nil),
+		testDeployment("auth", "authentik-worker", labels, nil),
+	}
+
+	findIngress := func(workloads ...Workload) (*api.Ingress, *api.HTTPRoute, bool) {
+		for _, workload := range workloads {
+			if workload.GetName() == "authentik-worker" {
+				return &api.Ingress{}, nil, true
+			}
+		}
+
+		return nil, nil, false
+	}
+
+	apps := groupApps(workloads, true, findIngress)
+	if len(apps) != 1 || apps[0].Workload.GetName() != "authentik-worker" {
+		t.Fatalf("expected workload with ingress to be the main workload")
+	}
+}
+
+func TestGroupApps_AnnotationsTakePrecedence(t *testing.T) {
+	labels := map[string]string{lInstance: "immich"}
+	workloads := WorkloadSlice{
+		testDeployment("media", "immich-server", labels, map[string]string{aId: "photos"}),
+		testDeployment("media", "immich-ml", labels, nil),
+		testDeployment("media", "postgres", nil, map[string]string{aParent: "photos"}),
+	}
+
+	apps := groupApps(workloads, true, noIngress)
+	if len(apps) != 2 {
+		t.Fatalf("got %d apps, want 2", len(apps))
+	}
+
+	app := findApp(apps, "media/immich-server")
+	if app == nil || len(app.Dependencies) != 1 || app.Dependencies[0].GetName() != "postgres" {
+		t.Fatalf("explicit annotations were not honored")
+	}
+}
+
+func TestGroupApps_IdsScopedToNamespace(t *testing.T) {
+	workloads := WorkloadSlice{
+		testDeployment("a", "web", nil, map[string]string{aId: "web"}),
+		testDeployment("b", "web", nil, map[string]string{aId: "web"}),
+		testDeployment("b", "db", nil, map[string]string{aParent: "web"}),
+		testDeployment("c", "cache", nil, map[string]string{aParent: "a/web"}),
+	}
+
+	apps := groupApps(workloads, false, noIngress)
+	if len(apps) != 2 {
+		t.Fatalf("got %d apps, want 2", len(apps))
+	}
+
+	for _, app := range apps {
+		if len(app.Warnings) > 0 {
+			t.Fatalf("unexpected warnings: %v", app.Warnings)
+		}
+
+		if len(app.Dependencies) != 1 {
+			t.Fatalf("app %s: got %d dependencies, want 1", resourceFullname(app.Workload), len(app.Dependencies))
+		}
+	}
+}
+
+func TestGroupApps_DuplicateIdWarns(t *testing.T) {
+	workloads := WorkloadSlice{
+		testDeployment("a", "first", nil, map[string]string{aId: "web"}),
+		testDeployment("a", "second", nil, map[string]string{aId: "web"}),
+	}
+
+	apps := groupApps(workloads, false, noIngress)
+	if len(apps) != 1 {
+		t.Fatalf("got %d apps, want 1", len(apps))
+	}
+
+	app := apps[0]
+	if app.Workload.GetName() != "first" {
+		t.Fatalf("got main workload %q, want %q", app.Workload.GetName(), "first")
+	}
+
+	if len(app.Warnings) != 1 {
+		t.Fatalf("got %d warnings, want 1", len(app.Warnings))
+	}
+}
+
+func TestBuildShowIfFilterFunc_ExpandedEnvironment(t *testing.T) {
+	filter, err := buildShowIfFilterFunc([]string{`kind != "DaemonSet" and ready == false and labels["tier"] == "web" and version == "2.0" and "v2.0.1" in tags`})
+	if err != nil {
+		t.Fatalf("could not build filter: %v", err)
+	}
+
+	workload := testDeployment("a", "web", map[string]string{"tier": "web", lVersion: "2.0"}, nil).(*deployment)
+	workload.Spec.Template.Spec.Containers = []api.Container{{Name: "web", Image: "example/web:v2.0.1"}}
+	workload.Status.Replicas = 2
+	workload.Status.ReadyReplicas = 1
+
+	if !filter(&App{Workload: workload}) {
+		t.Fatalf("expected unready deployment to be shown")
+	}
+
+	workload.Status.ReadyReplicas = 2
+
+	if filter(&App{Workload: workload}) {
+		t.Fatalf("expected ready deployment to be hidden")
+	}
+}
+
+func TestApplyDisplayExpressions(t *testing.T) {
+	app := &App{
+		Workload:    testDeployment("a", "web", nil, nil),
+		Annotations: map[string]string{"app.kubernetes.io/version": "1.2.3"},
+	}
+
+	err := applyDisplayExpressions(AppSlice{app}, AppsOptions{
+		NameExpr:        `upper(name)`,
+		DescriptionExpr: `annotations["app.kubernetes.io/version"] ?? ""`,
+		IconExpr:        `annotations["missing"] ?? ""`,
+	})
+	if err != nil {
+		t.Fatalf("could not apply display expressions: %v", err)
+	}
+
+	if got := app.Name(); got != "WEB" {
+		t.Fatalf("name = %q, want %q", got, "WEB")
+	}
+
+	if got := app.Description(); got != "1.2.3" {
+		t.Fatalf("description = %q, want %q", got, "1.2.3")
+	}
+
+	if got := app.Icon(); got != "di:kubernetes" {
+		t.Fatalf("icon = %q, want default", got)
+	}
+}
+
+func TestApp_Maintenance(t *testing.T) {
+	tests := []struct {
+		annotations          map[string]string
+		namespaceAnnotations map[string]string
+		want                 bool
+		reason               string
+	}{
+		{annotations: nil, want: false},
+		{annotations: map[string]string{aMaintenance: "false"}, want: false},
+		{annotations: map[string]string{aMaintenance: "true"}, want: true},
+		{annotations: map[string]string{aMaintenance: "Upgrading"}, want: true, reason: "Upgrading"},
+		{annotations: map[string]string{aMaintenance: "true", aMaintenanceUntil: "2000-01-01T00:00:00Z"}, want: false},
+		{annotations: map[string]string{aMaintenance: "true", aMaintenanceUntil: "2999-01-01T00:00:00Z"}, want: true},
+		{namespaceAnnotations: map[string]string{aMaintenance: "Node upgrade"}, want: true, reason: "Node upgrade"},
+		{annotations: map[string]string{aMaintenance: "false"}, namespaceAnnotations: map[string]string{aMaintenance: "true"}, want: false},
+		{annotations: map[string]string{aMaintenance: "true", aMaintenanceUntil: "2000-01-01T00:00:00Z"}, namespaceAnnotations: map[string]string{aMaintenance: "Node upgrade"}, want: true, reason: "Node upgrade"},
+		{annotations: map[string]string{aMaintenance: "true", aMaintenanceUntil: "soon"}, want: true},
+	}
+
+	for i, test := range tests {
+		app := &App{
+			Workload:    testDeployment("a", "web", nil, nil),
+			Annotations: test.annotations,
+			Namespace:   &api.Namespace{ObjectMeta: api.ObjectMeta{Annotations: test.namespaceAnnotations}},
+		}
+
+		app.maintenances, _ = parseMaintenances(app)
+
+		maintenance := app.Maintenance()
+		if got := maintenance != nil; got != test.want {
+			t.Errorf("%d: maintenance = %v, want %v", i, got, test.want)
+		}
+
+		if maintenance != nil && maintenance.Reason != test.reason {
+			t.Errorf("%d: reason = %q, want %q", i, maintenance.Reason, test.reason)
+		}
+	}
+}
+
+func TestSortApps(t *testing.T) {
+	newApp := func(namespace, name string, ready bool, annotations map[string]string) *App {
+		workload := testDeployment(namespace, name, nil, nil).(*deployment)
+		workload.Status.Replicas = 1
+		if ready {
+			workload.Status.ReadyReplicas = 1
+		}
+
+		return &App{Workload: workload, Annotations: annotations}
+	}
+
+	names := func(apps AppSlice) []string {
+		return lo.Map(apps, func(app *App, _ int) string { return app.Workload.GetName() })
+	}
+
+	apps := AppSlice{
+		newApp("b", "alpha", true, nil),
+		newApp("a", "Charlie", false, nil),
+		newApp("a", "bravo", true, map[string]string{aOrder: "2"}),
+		newApp("b", "delta", true, map[string]string{aOrder: "1"}),
+	}
+
+	tests := []struct {
+		sortBy string
+		want   []string
+	}{
+		{sortBy: "", want: []string{"delta", "bravo", "alpha", "Charlie"}},
+		{sortBy: "namespace", want: []string{"delta", "bravo", "Charlie", "alpha"}},
+		{sortBy: "status", want: []string{"delta", "bravo", "Charlie", "alpha"}},
+	}
+
+	for _, test := range tests {
+		if err := sortApps(apps, test.sortBy); err != nil {
+			t.Fatalf("could not sort by %q: %v", test.sortBy, err)
+		}
+
+		if got := names(apps); !slices.Equal(got, test.want) {
+			t.Errorf("sort by %q = %v, want %v", test.sortBy, got, test.want)
+		}
+	}
+
+	if err := sortApps(apps, "size"); err == nil {
+		t.Error("expected an error for an unknown sort-by")
+	}
+}
+
+func TestAppSlice_GroupBy(t *testing.T) {
+	apps := AppSlice{
+		{Workload: testDeployment("a", "web", nil, nil), Annotations: map[string]string{aCategory: "Media"}},
+		{Workload: testDeployment("a", "db", nil, nil)},
+		{Workload: testDeployment("b", "auth", nil, nil), Annotations: map[string]string{aCategory: "admin"}},
+	}
+
+	groups, err := apps.GroupBy("category")
+	if err != nil {
+		t.Fatalf("could not group: %v", err)
+	}
+
+	titles := lo.Map(groups, func(group AppGroup, _ int) string { return group.Title })
+	if want := []string{"admin", "Media", uncategorized}; !slices.Equal(titles, want) {
+		t.Fatalf("titles = %v, want %v", titles, want)
+	}
+
+	if groups, _ := apps.GroupBy(""); len(groups) != 1 || len(groups[0].Apps) != 3 {
+		t.Fatalf("expected a single group without group-by")
+	}
+}
+
+func TestLimitApps_KeepsUnhealthy(t *testing.T) {
+	newApp := func(name string, ready bool) *App {
+		workload := testDeployment("a", name, nil, nil).(*deployment)
+		workload.Status.Replicas = 1
+		if ready {
+			workload.Status.ReadyReplicas = 1
+		}
+
+		return &App{Workload: workload}
+	}
+
+	apps := AppSlice{newApp("a", true), newApp("b", true), newApp("c", false), newApp("d", false)}
+	pinUnhealthy(apps)
+
+	names := lo.Map(limitApps(apps, 1), func(app *App, _ int) string { return app.Workload.GetName() })
+	if want := []string{"c", "d"}; !slices.Equal(names, want) {
+		t.Fatalf("limited = %v, want %v", names, want)
+	}
+
+	if got := len(limitApps(apps, 3)); got != 3 {
+		t.Fatalf("len = %d, want 3", got)
+	}
+
+	groups := []AppGroup{{Apps: apps}}
+	collapseGroups(groups, 1)
+
+	if got := groups[0].CollapseAfter; got != 2 {
+		t.Fatalf("collapse after = %d, want 2", got)
+	}
+}
+
+func TestMergeAnnotations_Precedence(t *testing.T) {
+	main := testDeployment("a", "web", nil, map[string]string{aName: "Workload", aIcon: "di:workload", aUrl: "https://workload"})
+	main.(*deployment).Spec.Template.Labels = map[string]string{"app": "web"}
+	dependency := testDeployment("a", "db", nil, nil)
+	dependency.(*deployment).Spec.Template.Labels = map[string]string{"app": "db"}
+
+	services := []api.Service{
+		{
+			ObjectMeta: api.ObjectMeta{Namespace: "a", Name: "db", Annotations: map[string]string{aIcon: "di:db", aDescription: "Database"}},
+			Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "db"}},
+		},
+		{
+			ObjectMeta: api.ObjectMeta{Namespace: "a", Name: "web", Annotations: map[string]string{aIcon: "di:service", aUrl: "https://service"}},
+			Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "web"}, Ports: []corev1.ServicePort{{Port: 80}, {Port: 443}}},
+		},
+		{ObjectMeta: api.ObjectMeta{Namespace: "a", Name: "external"}},
+	}
+
+	app := &App{Workload: main, Dependencies: WorkloadSlice{dependency}}
+	app.Services = findServices(services, main, dependency)
+	app.Ingress = &api.Ingress{ObjectMeta: api.ObjectMeta{Annotations: map[string]string{aUrl: "https://ingress"}}}
+	app.Annotations = mergeAnnotations(app)
+
+	names := lo.Map(app.Services, func(service api.Service, _ int) string { return service.Name })
+	if want := []string{"web", "db"}; !slices.Equal(names, want) {
+		t.Fatalf("services = %v, want %v", names, want)
+	}
+
+	want := map[string]string{
+		aName:        "Workload",
+		aIcon:        "di:service",
+		aDescription: "Database",
+		aUrl:         "https://ingress",
+	}
+
+	for key, value := range want {
+		if got := app.Annotations[key]; got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+
+	filter, err := buildShowIfFilterFunc([]string{`"web" in services and 443 in ports`})
+	if err != nil {
+		t.Fatalf("could not build filter: %v", err)
+	}
+
+	if !filter(app) {
+		t.Fatal("expected app to be shown")
+	}
+}
@@ -15,8 +15,19 @@ type apiClient interface {
 	Services(ctx context.Context) ([]api.Service, error)
 	Ingresses(ctx context.Context) ([]api.Ingress, error)
 	HTTPRoutes(ctx context.Context) ([]api.HTTPRoute, error)
+	EndpointSlices(ctx context.Context) ([]api.EndpointSlice, error)
+	Namespaces(ctx context.Context) ([]api.Namespace, error)
 	Nodes(ctx context.Context) ([]api.Node, error)
 	NodeMetrics(ctx context.Context) ([]api.NodeMetrics, error)
+	Pods(ctx context.Context) ([]api.Pod, error)
+	WarningEvents(ctx context.Context) ([]api.Event, error)
+	PodMetrics(ctx context.Context) ([]api.PodMetrics, error)
+	PersistentVolumeClaims(ctx context.Context) ([]api.PersistentVolumeClaim, error)
+	VolumeStats(ctx context.Context) ([]api.VolumeStats, error)
+	Gateways(ctx context.Context) ([]api.Gateway, error)
+	Certificates(ctx context.Context) ([]api.Certificate, error)
+	TLSSecrets(ctx context.Context) ([]api.Secret, error)
+	HelmReleaseSecrets(ctx context.Context) ([]api.Secret, error)
 }
 
 // cachedClient wraps an apiClient with one read-through cache per
@@ -30,8 +41,19 @@ type cachedClient struct {
 	services     cache[api.Service]
 	ingresses    cache[api.Ingress]
 	httpRoutes   cache[api.HTTPRoute]
+	endpoints    cache[api.EndpointSlice]
+	namespaces   cache[api.Namespace]
 	nodes        cache[api.Node]
 	nodeMetrics  cache[api.NodeMetrics]
+	pods         cache[api.Pod]
+	events       cache[api.Event]
+	podMetrics   cache[api.PodMetrics]
+	claims       cache[api.PersistentVolumeClaim]
+	volumeStats  cache[api.VolumeStats]
+	gateways     cache[api.Gateway]
+	certificates cache[api.Certificate]
+	tlsSecrets   cache[api.Secret]
+	helmSecrets  cache[api.Secret]
 }
 
 func newCachedClient(inner apiClient) *cachedClient {
@@ -62,6 +84,14 @@ func (c *cachedClient) HTTPRoutes(ctx context.Context) ([]api.HTTPRoute, error)
 	return c.httpRoutes.get(ctx, c.inner.HTTPRoutes)
 }
 
+func (c *cachedClient) EndpointSlices(ctx context.Context) ([]api.EndpointSlice, error) {
+	return c.endpoints.get(ctx, c.inner.EndpointSlices)
+}
+
+func (c *cachedClient) Namespaces(ctx context.Context) ([]api.Namespace, error) {
+	return c.namespaces.get(ctx, c.inner.Namespaces)
+}
+
 func (c *cachedClient) Nodes(ctx context.Context) ([]api.Node, error) {
 	return c.nodes.get(ctx, c.inner.Nodes)
 }
@@ -69,3 +99,39 @@ func (c *cachedClient) Nodes(ctx context.Context) ([]api.Node, error) {
 func (c *cachedClient) NodeMetrics(ctx context.Context) ([]api.NodeMetrics, error) {
 	return c.nodeMetrics.get(ctx, c.inner.NodeMetrics)
 }
+
+func (c *cachedClient) Pods(ctx context.Context) ([]api.Pod, error) {
+	return c.pods.get(ctx, c.inner.Pods)
+}
+
+func (c *cachedClient) WarningEvents(ctx context.Context) ([]api.Event, error) {
+	return c.events.get(ctx, c.inner.WarningEvents)
+}
+
+func (c *cachedClient) PodMetrics(ctx context.Context) ([]api.PodMetrics, error) {
+	return c.podMetrics.get(ctx, c.inner.PodMetrics)
+}
+
+func (c *cachedClient) PersistentVolumeClaims(ctx context.Context) ([]api.PersistentVolumeClaim, error) {
+	return c.claims.get(ctx, c.inner.PersistentVolumeClaims)
+}
+
+func (c *cachedClient) VolumeStats(ctx context.Context) ([]api.VolumeStats, error) {
+	return c.volumeStats.get(ctx, c.inner.VolumeStats)
+}
+
+func (c *cachedClient) Gateways(ctx context.Context) ([]api.Gateway, error) {
+	return c.gateways.get(ctx, c.inner.Gateways)
+}
+
+func (c *cachedClient) Certificates(ctx context.Context) ([]api.Certificate, error) {
+	return c.certificates.get(ctx, c.inner.Certificates)
+}
+
+func (c *cachedClient) TLSSecrets(ctx context.Context) ([]api.Secret, error) {
+	return c.tlsSecrets.get(ctx, c.inner.TLSSecrets)
+}
+
+func (c *cachedClient) HelmReleaseSecrets(ctx context.Context) ([]api.Secret, error) {
+	return c.helmSecrets.get(ctx, c.inner.HelmReleaseSecrets)
+}
@@ -0,0 +1,308 @@
+package k8s
+
+import (
+	"cmp"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/lukasdietrich/glance-k8s/internal/k8s/api"
+)
+
+const (
+	defaultCertificatesLimit = 10
+	defaultWarningDays       = 30
+	defaultCriticalDays      = 7
+)
+
+type CertificatesOptions struct {
+	Namespace []string
+	Limit     int
+	// Warning and Critical are the days left, below which a certificate is
+	// highlighted.
+	Warning  int
+	Critical int
+}
+
+// Certificate is a TLS certificate, either managed by cert-manager or read
+// from a kubernetes.io/tls secret.
+type Certificate struct {
+	Name      string
+	Namespace string
+	// Source is either "cert-manager" or "Secret".
+	Source   string
+	DNSNames []string
+	// NotAfter is zero, if the certificate was not issued yet.
+	NotAfter time.Time
+	Apps     []string
+	Warning  bool
+	Critical bool
+
+	secretName string
+}
+
+// Issued reports whether the certificate was issued.
+func (c Certificate) Issued() bool {
+	return !c.NotAfter.IsZero()
+}
+
+// DaysLeft returns the number of full days until the certificate expires,
+// which is negative if it already expired.
+func (c Certificate) DaysLeft() int {
+	return int(math.Floor(time.Until(c.NotAfter).Hours() / 24))
+}
+
+// secretKey returns the full name of the secret holding the certificate.
+func (c Certificate) secretKey() string {
+	return c.Namespace + "/" + c.secretName
+}
+
+// Certificates returns the certificates expiring first. Certificates managed
+// by cert-manager are used, where available. Other certificates are read from
+// the secrets referenced by ingresses and gateway listeners, if they can be
+// listed.
+func (c *Cluster) Certificates(ctx context.Context, opts CertificatesOptions) ([]Certificate, error) {
+	managed, err := c.client.Certificates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch certificates: %w", err)
+	}
+
+	ingresses, err := c.client.Ingresses(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch ingresses: %w", err)
+	}
+
+	gateways, err := c.client.Gateways(ctx)
+	if err != nil {
+		slog.Warn("could not fetch gateways", slog.Any("err", err))
+	}
+
+	secrets, err := c.client.TLSSecrets(ctx)
+	c.tlsSecretsState.observe("tls secrets", err)
+
+	apps, err := c.allApps(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	certificates := buildCertificates(managed, secretReferences(ingresses, gateways), secrets)
+	appsBySecret := secretApps(apps, gateways)
+
+	certificates = lo.Filter(certificates, func(certificate Certificate, _ int) bool {
+		return len(opts.Namespace) == 0 || slices.Contains(opts.Namespace, certificate.Namespace)
+	})
+
+	warning := cmp.Or(opts.Warning, defaultWarningDays)
+	critical := cmp.Or(opts.Critical, defaultCriticalDays)
+
+	for i, certificate := range certificates {
+		daysLeft := certificate.DaysLeft()
+
+		certificates[i].Critical = !certificate.Issued() || daysLeft < critical
+		certificates[i].Warning = daysLeft < warning
+	}
+
+	slices.SortStableFunc(certificates, func(a, b Certificate) int {
+		return cmp.Or(
+			a.NotAfter.Compare(b.NotAfter),
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+
+	if limit := cmp.Or(opts.Limit, defaultCertificatesLimit); len(certificates) > limit {
+		certificates = certificates[:limit]
+	}
+
+	for i, certificate := range certificates {
+		certificates[i].Apps = appsBySecret[certificate.secretKey()]
+	}
+
+	return certificates, nil
+}
+
+// buildCertificates returns the cert-manager certificates and the certificates
+// of all referenced secrets, which are not managed by cert-manager.
+func buildCertificates(managed []api.Certificate, references []string, secrets []api.Secret) []Certificate {
+	var certificates []Certificate
+	managedSecrets := make(map[string]bool, len(managed))
+
+	for _, certificate := range managed {
+		managedSecrets[certificate.Namespace+"/"+certificate.Spec.SecretName] = true
+
+		var notAfter time.Time
+		if certificate.Status.NotAfter != nil {
+			notAfter = certificate.Status.NotAfter.Time
+		}
+
+		certificates = append(certificates, Certificate{
+			Name:       certificate.Name,
+			Namespace:  certificate.Namespace,
+			Source:     "cert-manager",
+			DNSNames:   certificate.Spec.DNSNames,
+			NotAfter:   notAfter,
+			secretName: certificate.Spec.SecretName,
+		})
+	}
+
+	secretsByName := lo.SliceToMap(secrets, func(secret api.Secret) (string, api.Secret) {
+		return resourceFullname(&secret), secret
+	})
+
+	for _, reference := range lo.Uniq(references) {
+		secret, ok := secretsByName[reference]
+		if !ok || managedSecrets[reference] {
+			continue
+		}
+
+		leaf, err := parseLeafCertificate(secret.Data[api.TLSCertKey])
+		if err != nil {
+			slog.Warn("could not parse certificate",
+				slog.String("secret", reference),
+				slog.Any("err", err),
+			)
+
+			continue
+		}
+
+		certificates = append(certificates, Certificate{
+			Name:       secret.Name,
+			Namespace:  secret.Namespace,
+			Source:     "Secret",
+			DNSNames:   leaf.DNSNames,
+			NotAfter:   leaf.NotAfter,
+			secretName: secret.Name,
+		})
+	}
+
+	return certificates
+}
+
+// parseLeafCertificate parses the first certificate of a PEM encoded chain.
+func parseLeafCertificate(chain []byte) (*x509.Certificate, error) {
+	for {
+		var block *pem.Block
+
+		block, chain = pem.Decode(chain)
+		if block == nil {
+			return nil, fmt.Errorf("no certificate found")
+		}
+
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+}
+
+// secretReferences returns the full names of all secrets referenced by the
+// tls section of ingresses and the listeners of gateways.
+func secretReferences(ingresses []api.Ingress, gateways []api.Gateway) []string {
+	var references []string
+
+	for _, ingress := range ingresses {
+		references = append(references, ingressSecrets(ingress)...)
+	}
+
+	for _, gateway := range gateways {
+		references = append(references, gatewaySecrets(gateway, "")...)
+	}
+
+	return references
+}
+
+func ingressSecrets(ingress api.Ingress) []string {
+	var references []string
+
+	for _, tls := range ingress.Spec.TLS {
+		if tls.SecretName != "" {
+			references = append(references, ingress.Namespace+"/"+tls.SecretName)
+		}
+	}
+
+	return references
+}
+
+// gatewaySecrets returns the secrets referenced by the listeners of the
+// gateway. If section is not empty, only the listener of that name is used.
+func gatewaySecrets(gateway api.Gateway, section string) []string {
+	var references []string
+
+	for _, listener := range gateway.Spec.Listeners {
+		if listener.TLS == nil || (section != "" && string(listener.Name) != section) {
+			continue
+		}
+
+		for _, ref := range listener.TLS.CertificateRefs {
+			if ref.Kind != nil && *ref.Kind != "Secret" {
+				continue
+			}
+
+			namespace := gateway.Namespace
+			if ref.Namespace != nil {
+				namespace = string(*ref.Namespace)
+			}
+
+			references = append(references, namespace+"/"+string(ref.Name))
+		}
+	}
+
+	return references
+}
+
+// secretApps returns the names of the apps served by the certificate in each
+// secret, keyed by the full name of the secret.
+func secretApps(apps AppSlice, gateways []api.Gateway) map[string][]string {
+	gatewaysByName := lo.SliceToMap(gateways, func(gateway api.Gateway) (string, api.Gateway) {
+		return resourceFullname(&gateway), gateway
+	})
+
+	appsBySecret := make(map[string][]string)
+
+	for _, app := range apps {
+		var references []string
+
+		if app.Ingress != nil {
+			references = append(references, ingressSecrets(*app.Ingress)...)
+		}
+
+		if app.HTTPRoute != nil {
+			for _, parentRef := range app.HTTPRoute.Spec.ParentRefs {
+				if parentRef.Kind != nil && *parentRef.Kind != "Gateway" {
+					continue
+				}
+
+				namespace := app.HTTPRoute.Namespace
+				if parentRef.Namespace != nil {
+					namespace = string(*parentRef.Namespace)
+				}
+
+				var section string
+				if parentRef.SectionName != nil {
+					section = string(*parentRef.SectionName)
+				}
+
+				if gateway, ok := gatewaysByName[namespace+"/"+string(parentRef.Name)]; ok {
+					references = append(references, gatewaySecrets(gateway, section)...)
+				}
+			}
+		}
+
+		for _, reference := range lo.Uniq(references) {
+			appsBySecret[reference] = append(appsBySecret[reference], app.Name())
+		}
+	}
+
+	for reference, names := range appsBySecret {
+		slices.Sort(names)
+		appsBySecret[reference] = slices.Compact(names)
+	}
+
+	return appsBySecret
+}
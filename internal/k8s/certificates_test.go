@@ -0,0 +1,123 @@
+package k8s
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"slices"
+	"testing"
+	"time"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/lukasdietrich/glance-k8s/internal/k8s/api"
+)
+
+func testCertificatePEM(t *testing.T, notAfter time.Time) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		DNSNames:     []string{"example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     notAfter,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestBuildCertificates(t *testing.T) {
+	notAfter := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+
+	secret := func(name string) api.Secret {
+		return api.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "web", Name: name},
+			Data:       map[string][]byte{api.TLSCertKey: testCertificatePEM(t, notAfter)},
+		}
+	}
+
+	var managed api.Certificate
+	managed.Namespace, managed.Name = "web", "managed"
+	managed.Spec.SecretName = "managed-tls"
+
+	certificates := buildCertificates(
+		[]api.Certificate{managed},
+		[]string{"web/managed-tls", "web/manual-tls", "web/manual-tls", "web/missing-tls"},
+		[]api.Secret{secret("managed-tls"), secret("manual-tls"), secret("unused-tls")},
+	)
+
+	if len(certificates) != 2 {
+		t.Fatalf("certificates = %+v, want managed and manual", certificates)
+	}
+
+	if c := certificates[0]; c.Source != "cert-manager" || c.Issued() || c.secretKey() != "web/managed-tls" {
+		t.Errorf("managed = %+v, want unissued cert-manager certificate", c)
+	}
+
+	if c := certificates[1]; c.Source != "Secret" || !c.NotAfter.Equal(notAfter) || c.DaysLeft() != 1 || !slices.Equal(c.DNSNames, []string{"example.com"}) {
+		t.Errorf("manual = %+v, want parsed from secret", c)
+	}
+}
+
+func TestSecretApps(t *testing.T) {
+	section := gatewayapiv1.SectionName("https")
+
+	gateway := api.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "infra", Name: "public"},
+		Spec: gatewayapiv1.GatewaySpec{
+			Listeners: []gatewayapiv1.Listener{
+				{Name: "https", TLS: &gatewayapiv1.ListenerTLSConfig{CertificateRefs: []gatewayapiv1.SecretObjectReference{{Name: "wildcard-tls"}}}},
+				{Name: "other", TLS: &gatewayapiv1.ListenerTLSConfig{CertificateRefs: []gatewayapiv1.SecretObjectReference{{Name: "other-tls"}}}},
+			},
+		},
+	}
+
+	namespace := gatewayapiv1.Namespace("infra")
+
+	routed := &App{
+		Workload: testDeployment("web", "routed", nil, nil),
+		HTTPRoute: &api.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "web"},
+			Spec: gatewayapiv1.HTTPRouteSpec{CommonRouteSpec: gatewayapiv1.CommonRouteSpec{
+				ParentRefs: []gatewayapiv1.ParentReference{{Name: "public", Namespace: &namespace, SectionName: &section}},
+			}},
+		},
+	}
+
+	ingressed := &App{
+		Workload: testDeployment("web", "ingressed", nil, nil),
+		Ingress: &api.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "web"},
+			Spec:       networkingv1.IngressSpec{TLS: []networkingv1.IngressTLS{{SecretName: "web-tls"}}},
+		},
+	}
+
+	appsBySecret := secretApps(AppSlice{routed, ingressed}, []api.Gateway{gateway})
+
+	if got := appsBySecret["infra/wildcard-tls"]; !slices.Equal(got, []string{"Routed"}) {
+		t.Errorf("wildcard = %v, want [Routed]", got)
+	}
+
+	if got := appsBySecret["infra/other-tls"]; got != nil {
+		t.Errorf("other = %v, want none", got)
+	}
+
+	if got := appsBySecret["web/web-tls"]; !slices.Equal(got, []string{"Ingressed"}) {
+		t.Errorf("web = %v, want [Ingressed]", got)
+	}
+}
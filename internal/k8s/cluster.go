@@ -1,9 +1,25 @@
 package k8s
 
-import "github.com/lukasdietrich/glance-k8s/internal/k8s/api"
+import (
+	"context"
+	"os"
+	"sync"
+
+	"github.com/lukasdietrich/glance-k8s/internal/k8s/api"
+)
 
 type Cluster struct {
-	client apiClient
+	client     apiClient
+	imageIcons imageIcons
+	staticApps []staticWorkload
+	routeApps  bool
+	prober     *prober
+	history    *history
+	notifier   *notifier
+
+	podMetricsState  fetchState
+	volumeStatsState fetchState
+	tlsSecretsState  fetchState
 }
 
 func Connect() (*Cluster, error) {
@@ -12,5 +28,120 @@ func Connect() (*Cluster, error) {
 		return nil, err
 	}
 
-	return &Cluster{client: newCachedClient(client)}, nil
+	imageIcons, err := loadImageIcons(os.Getenv("GLANCE_ICON_MAPPING"))
+	if err != nil {
+		return nil, err
+	}
+
+	staticApps, err := loadStaticApps(os.Getenv("GLANCE_STATIC_APPS"))
+	if err != nil {
+		return nil, err
+	}
+
+	prober, err := newProber(os.Getenv("GLANCE_PROBE_INTERVAL"))
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := newHistory(os.Getenv("GLANCE_HISTORY_INTERVAL"), os.Getenv("GLANCE_HISTORY_FILE"))
+	if err != nil {
+		return nil, err
+	}
+
+	notifier, err := newNotifier(os.Getenv("GLANCE_NOTIFY_CONFIG"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cluster{
+		client:     newCachedClient(client),
+		imageIcons: imageIcons,
+		staticApps: staticApps,
+		routeApps:  os.Getenv("GLANCE_ROUTE_APPS") == "true",
+		prober:     prober,
+		history:    history,
+		notifier:   notifier,
+	}, nil
+}
+
+// Run starts the enabled background tasks and blocks until ctx is done.
+func (c *Cluster) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	if c.prober != nil {
+		wg.Go(func() { c.prober.run(ctx, c.allApps) })
+	}
+
+	if c.history != nil {
+		wg.Go(func() { c.history.run(ctx, c.sampleSubjects) })
+	}
+
+	if c.notifier != nil {
+		wg.Go(func() { c.notifier.run(ctx, c.sampleSubjects) })
+	}
+
+	wg.Wait()
+}
+
+// allApps returns all apps without any filters applied.
+func (c *Cluster) allApps(ctx context.Context) (AppSlice, error) {
+	return c.Apps(ctx, AppsOptions{})
+}
+
+// subjectSample is the sampled state of an app or node.
+type subjectSample struct {
+	Kind      string
+	Name      string
+	Namespace string
+	Url       string
+	State     subjectState
+}
+
+// sampleSubjects returns the current state of all apps and nodes keyed by
+// their subject key.
+func (c *Cluster) sampleSubjects(ctx context.Context) (map[string]subjectSample, error) {
+	apps, err := c.allApps(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := c.Nodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	subjects := make(map[string]subjectSample, len(apps)+len(nodes))
+
+	for _, app := range apps {
+		state := readyState(app.Ready())
+		if app.Maintenance() != nil {
+			state = stateMaintenance
+		}
+
+		subjects[app.subjectKey()] = subjectSample{
+			Kind:      "app",
+			Name:      app.Name(),
+			Namespace: app.Workload.GetNamespace(),
+			Url:       app.Url(),
+			State:     state,
+		}
+	}
+
+	for _, node := range nodes {
+		subjects[node.subjectKey()] = subjectSample{
+			Kind:  "node",
+			Name:  node.Name,
+			State: readyState(node.ConditionTrue("Ready")),
+		}
+	}
+
+	return subjects, nil
+}
+
+func readyState(ready bool) subjectState {
+	if ready {
+		return stateUp
+	}
+
+	return stateDown
 }
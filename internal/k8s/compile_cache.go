@@ -0,0 +1,90 @@
+package k8s
+
+import (
+	"container/list"
+	"expvar"
+	"sync"
+)
+
+// compileCacheSize bounds the number of compiled expressions kept per
+// cache. Glance sends the same parameters on every refresh, so even large
+// dashboards only use a few distinct expressions.
+const compileCacheSize = 256
+
+// compileCache memoizes compiled expressions keyed by their source text.
+// Compile errors are cached as well, so a broken expression is only
+// compiled once. When full, the least recently used entry is evicted.
+type compileCache[T any] struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   list.List
+	compile func(string) (T, error)
+
+	hits      expvar.Int
+	misses    expvar.Int
+	evictions expvar.Int
+}
+
+type compileCacheEntry[T any] struct {
+	source string
+	value  T
+	err    error
+}
+
+func newCompileCache[T any](size int, compile func(string) (T, error)) *compileCache[T] {
+	return &compileCache[T]{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		compile: compile,
+	}
+}
+
+// publish exposes hits, misses, evictions and the hit rate via expvar
+// under name. It panics if name is already in use.
+func (c *compileCache[T]) publish(name string) *compileCache[T] {
+	stats := expvar.NewMap(name)
+	stats.Set("hits", &c.hits)
+	stats.Set("misses", &c.misses)
+	stats.Set("evictions", &c.evictions)
+	stats.Set("hitRate", expvar.Func(c.hitRate))
+
+	return c
+}
+
+// get returns the compiled form of source, compiling it on a miss.
+func (c *compileCache[T]) get(source string) (T, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[source]; ok {
+		c.hits.Add(1)
+		c.order.MoveToFront(element)
+
+		entry := element.Value.(*compileCacheEntry[T])
+		return entry.value, entry.err
+	}
+
+	c.misses.Add(1)
+
+	value, err := c.compile(source)
+	c.entries[source] = c.order.PushFront(&compileCacheEntry[T]{source: source, value: value, err: err})
+
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*compileCacheEntry[T]).source)
+		c.evictions.Add(1)
+	}
+
+	return value, err
+}
+
+func (c *compileCache[T]) hitRate() any {
+	hits, misses := c.hits.Value(), c.misses.Value()
+	if hits+misses == 0 {
+		return 0.0
+	}
+
+	return float64(hits) / float64(hits+misses)
+}
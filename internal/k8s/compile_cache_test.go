@@ -0,0 +1,64 @@
+package k8s
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCompileCache_HitAndErrorCached(t *testing.T) {
+	var calls int
+	wantErr := errors.New("broken")
+	c := newCompileCache(4, func(source string) (string, error) {
+		calls++
+		if source == "broken" {
+			return "", wantErr
+		}
+		return source + "!", nil
+	})
+
+	for i := 0; i < 3; i++ {
+		if got, err := c.get("ok"); err != nil || got != "ok!" {
+			t.Fatalf("call %d: got %q err %v", i, got, err)
+		}
+
+		if _, err := c.get("broken"); !errors.Is(err, wantErr) {
+			t.Fatalf("call %d: err = %v, want %v", i, err, wantErr)
+		}
+	}
+
+	if calls != 2 {
+		t.Fatalf("compile called %d times, want 2", calls)
+	}
+
+	if got := c.hitRate().(float64); got != 4.0/6.0 {
+		t.Fatalf("hit rate = %v, want %v", got, 4.0/6.0)
+	}
+}
+
+func TestCompileCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	var calls int
+	c := newCompileCache(2, func(source string) (string, error) {
+		calls++
+		return source, nil
+	})
+
+	_, _ = c.get("a")
+	_, _ = c.get("b")
+	_, _ = c.get("a") // a is now more recent than b
+	_, _ = c.get("c") // evicts b
+	_, _ = c.get("a")
+
+	if calls != 3 {
+		t.Fatalf("compile called %d times, want 3", calls)
+	}
+
+	_, _ = c.get("b")
+
+	if calls != 4 {
+		t.Fatalf("compile called %d times, want 4", calls)
+	}
+
+	if got := c.evictions.Value(); got != 2 {
+		t.Fatalf("evictions = %d, want 2", got)
+	}
+}
@@ -0,0 +1,202 @@
+package k8s
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/lukasdietrich/glance-k8s/internal/k8s/api"
+)
+
+const defaultEventsLimit = 10
+
+var eventShowIfCache = newCompileCache(compileCacheSize, compileShowIf[eventEnv]).publish("cache.eventShowIf")
+
+type EventsOptions struct {
+	Namespace []string
+	Reason    []string
+	ShowIf    []string
+	Limit     int
+	// MaxAge hides events last seen before, e.g. "1h". Empty shows all.
+	MaxAge string
+}
+
+// EventGroup is the deduplicated warning events of a single object.
+type EventGroup struct {
+	Kind      string
+	Name      string
+	Namespace string
+	Events    []Event
+	Count     int
+	LastSeen  time.Time
+}
+
+// Event is a warning, deduplicated by reason and message.
+type Event struct {
+	Reason   string
+	Message  string
+	Count    int
+	LastSeen time.Time
+}
+
+// eventEnv is the environment of show-if expressions of the events widget.
+type eventEnv struct {
+	Kind       string            `expr:"kind"`
+	Name       string            `expr:"name"`
+	Namespace  string            `expr:"namespace"`
+	Reason     string            `expr:"reason"`
+	Message    string            `expr:"message"`
+	Count      int               `expr:"count"`
+	Controller string            `expr:"controller"`
+	Labels     map[string]string `expr:"labels"`
+}
+
+func newEventEnv(event api.Event) eventEnv {
+	return eventEnv{
+		Kind:       event.Regarding.Kind,
+		Name:       event.Regarding.Name,
+		Namespace:  event.Regarding.Namespace,
+		Reason:     event.Reason,
+		Message:    event.Note,
+		Count:      eventCount(event),
+		Controller: event.ReportingController,
+		Labels:     event.Labels,
+	}
+}
+
+// Events returns recent warning events grouped by the object they are about,
+// the most recent first.
+func (c *Cluster) Events(ctx context.Context, opts EventsOptions) ([]EventGroup, error) {
+	var cutoff time.Time
+
+	if opts.MaxAge != "" {
+		maxAge, err := time.ParseDuration(opts.MaxAge)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse max-age: %w", err)
+		}
+
+		cutoff = time.Now().Add(-maxAge)
+	}
+
+	programs, err := showIfPrograms(eventShowIfCache, opts.ShowIf)
+	if err != nil {
+		return nil, fmt.Errorf("could not build show-if filter: %w", err)
+	}
+
+	events, err := c.client.WarningEvents(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch events: %w", err)
+	}
+
+	events = lo.Filter(events, func(event api.Event, _ int) bool {
+		if (len(opts.Namespace) > 0 && !slices.Contains(opts.Namespace, event.Regarding.Namespace)) ||
+			(len(opts.Reason) > 0 && !slices.Contains(opts.Reason, event.Reason)) ||
+			eventLastSeen(event).Before(cutoff) {
+			return false
+		}
+
+		env := newEventEnv(event)
+		return matchesShowIf(programs, &env)
+	})
+
+	groups := groupEvents(events)
+	if limit := cmp.Or(opts.Limit, defaultEventsLimit); len(groups) > limit {
+		groups = groups[:limit]
+	}
+
+	return groups, nil
+}
+
+// groupEvents groups events by the object they are about and deduplicates
+// events with the same reason and message, summing up their counts.
+func groupEvents(events []api.Event) []EventGroup {
+	type eventKey struct {
+		reason  string
+		message string
+	}
+
+	var groups []EventGroup
+	groupIndices := make(map[string]int)
+	eventIndices := make(map[string]map[eventKey]int)
+
+	for _, event := range events {
+		regarding := event.Regarding
+		groupKey := fmt.Sprintf("%s/%s/%s", regarding.Kind, regarding.Namespace, regarding.Name)
+
+		i, ok := groupIndices[groupKey]
+		if !ok {
+			i = len(groups)
+			groupIndices[groupKey] = i
+			eventIndices[groupKey] = make(map[eventKey]int)
+			groups = append(groups, EventGroup{
+				Kind:      regarding.Kind,
+				Name:      regarding.Name,
+				Namespace: regarding.Namespace,
+			})
+		}
+
+		group := &groups[i]
+		count, lastSeen := eventCount(event), eventLastSeen(event)
+
+		group.Count += count
+		if lastSeen.After(group.LastSeen) {
+			group.LastSeen = lastSeen
+		}
+
+		key := eventKey{reason: event.Reason, message: event.Note}
+		if j, ok := eventIndices[groupKey][key]; ok {
+			group.Events[j].Count += count
+			if lastSeen.After(group.Events[j].LastSeen) {
+				group.Events[j].LastSeen = lastSeen
+			}
+		} else {
+			eventIndices[groupKey][key] = len(group.Events)
+			group.Events = append(group.Events, Event{
+				Reason:   event.Reason,
+				Message:  event.Note,
+				Count:    count,
+				LastSeen: lastSeen,
+			})
+		}
+	}
+
+	for _, group := range groups {
+		slices.SortStableFunc(group.Events, func(a, b Event) int {
+			return b.LastSeen.Compare(a.LastSeen)
+		})
+	}
+
+	slices.SortStableFunc(groups, func(a, b EventGroup) int {
+		return b.LastSeen.Compare(a.LastSeen)
+	})
+
+	return groups
+}
+
+// eventCount returns how often the event occurred, supporting both the
+// series of events.k8s.io/v1 and the count of core/v1 events.
+func eventCount(event api.Event) int {
+	if event.Series != nil {
+		return int(event.Series.Count)
+	}
+
+	return max(int(event.DeprecatedCount), 1)
+}
+
+// eventLastSeen returns the time the event was last observed.
+func eventLastSeen(event api.Event) time.Time {
+	switch {
+	case event.Series != nil:
+		return event.Series.LastObservedTime.Time
+	case !event.DeprecatedLastTimestamp.IsZero():
+		return event.DeprecatedLastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
@@ -0,0 +1,76 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	"github.com/expr-lang/expr/vm"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/lukasdietrich/glance-k8s/internal/k8s/api"
+)
+
+func testEvent(kind, name, reason, note string, count int32, lastSeen time.Time) api.Event {
+	return api.Event{
+		Regarding:               corev1.ObjectReference{Kind: kind, Namespace: "a", Name: name},
+		Reason:                  reason,
+		Note:                    note,
+		DeprecatedCount:         count,
+		DeprecatedLastTimestamp: metav1.NewTime(lastSeen),
+	}
+}
+
+func TestGroupEvents(t *testing.T) {
+	now := time.Now()
+
+	events := []api.Event{
+		testEvent("Pod", "web", "BackOff", "Back-off restarting failed container", 3, now.Add(-10*time.Minute)),
+		testEvent("Pod", "db", "FailedMount", "volume not found", 1, now.Add(-5*time.Minute)),
+		testEvent("Pod", "web", "Unhealthy", "Readiness probe failed", 1, now.Add(-2*time.Minute)),
+		testEvent("Pod", "web", "BackOff", "Back-off restarting failed container", 2, now.Add(-1*time.Minute)),
+		{
+			Regarding: corev1.ObjectReference{Kind: "Node", Name: "node-1"},
+			Reason:    "Rebooted",
+			Series:    &eventsv1.EventSeries{Count: 4, LastObservedTime: metav1.NewMicroTime(now.Add(-30 * time.Minute))},
+		},
+	}
+
+	groups := groupEvents(events)
+	if len(groups) != 3 {
+		t.Fatalf("len = %d, want 3", len(groups))
+	}
+
+	web := groups[0]
+	if web.Name != "web" || web.Count != 6 || len(web.Events) != 2 {
+		t.Fatalf("web = %+v, want 6 events deduplicated to 2", web)
+	}
+
+	if backOff := web.Events[0]; backOff.Reason != "BackOff" || backOff.Count != 5 || !backOff.LastSeen.Equal(now.Add(-time.Minute)) {
+		t.Errorf("back off = %+v, want 5 occurrences last seen a minute ago", backOff)
+	}
+
+	if node := groups[2]; node.Kind != "Node" || node.Count != 4 {
+		t.Errorf("node = %+v, want 4 occurrences from the series", node)
+	}
+}
+
+func TestMatchesEventShowIf(t *testing.T) {
+	program, err := compileShowIf[eventEnv](`kind == "Pod" and reason != "BackOff" and count >= 1`)
+	if err != nil {
+		t.Fatalf("could not compile: %v", err)
+	}
+
+	programs := []*vm.Program{program}
+
+	env := newEventEnv(testEvent("Pod", "web", "Unhealthy", "", 1, time.Now()))
+	if !matchesShowIf(programs, &env) {
+		t.Error("expected event to match")
+	}
+
+	env = newEventEnv(testEvent("Pod", "web", "BackOff", "", 1, time.Now()))
+	if matchesShowIf(programs, &env) {
+		t.Error("expected event not to match")
+	}
+}
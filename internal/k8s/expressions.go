@@ -0,0 +1,204 @@
+package k8s
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"reflect"
+	"regexp"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+	"github.com/samber/lo"
+
+	"github.com/lukasdietrich/glance-k8s/internal/k8s/api"
+)
+
+const aIngressClass = "kubernetes.io/ingress.class"
+
+var (
+	showIfCache      = newCompileCache(compileCacheSize, compileShowIf[appEnv]).publish("cache.showIf")
+	hidePatternCache = newCompileCache(compileCacheSize, regexp.Compile).publish("cache.hidePattern")
+	displayCache     = newCompileCache(compileCacheSize, compileDisplay).publish("cache.display")
+)
+
+// compileShowIf compiles a show-if expression against the environment Env,
+// e.g. appEnv for apps.
+func compileShowIf[Env any](expression string) (*vm.Program, error) {
+	var env Env
+	return expr.Compile(expression, expr.Env(env), expr.AsBool(), expr.WarnOnAny())
+}
+
+// showIfPrograms returns the compiled show-if expressions from cache.
+func showIfPrograms(cache *compileCache[*vm.Program], expressions []string) ([]*vm.Program, error) {
+	return lo.MapErr(expressions, func(expression string, _ int) (*vm.Program, error) {
+		return cache.get(expression)
+	})
+}
+
+// matchesShowIf reports whether env satisfies all show-if programs. A failing
+// evaluation does not match.
+func matchesShowIf(programs []*vm.Program, env any) bool {
+	for _, program := range programs {
+		output, err := expr.Run(program, env)
+		if err != nil {
+			slog.Error("could not evaluate expression", slog.Any("err", err))
+			return false
+		}
+
+		if !output.(bool) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func compileDisplay(expression string) (*vm.Program, error) {
+	return expr.Compile(expression, expr.Env(appEnv{}), expr.AsKind(reflect.String))
+}
+
+// appDisplay holds the results of display expressions. Empty values do not
+// override the defaults of an app.
+type appDisplay struct {
+	name        string
+	description string
+	icon        string
+	url         string
+}
+
+func applyDisplayExpressions(apps AppSlice, opts AppsOptions) error {
+	fields := []struct {
+		expression string
+		target     func(*appDisplay) *string
+	}{
+		{opts.NameExpr, func(d *appDisplay) *string { return &d.name }},
+		{opts.DescriptionExpr, func(d *appDisplay) *string { return &d.description }},
+		{opts.IconExpr, func(d *appDisplay) *string { return &d.icon }},
+		{opts.UrlExpr, func(d *appDisplay) *string { return &d.url }},
+	}
+
+	programs := make([]*vm.Program, len(fields))
+	for i, field := range fields {
+		if field.expression == "" {
+			continue
+		}
+
+		program, err := displayCache.get(field.expression)
+		if err != nil {
+			return fmt.Errorf("could not compile %q: %w", field.expression, err)
+		}
+
+		programs[i] = program
+	}
+
+	for _, app := range apps {
+		var display appDisplay
+		env := newAppEnv(app)
+
+		for i, program := range programs {
+			if program == nil {
+				continue
+			}
+
+			output, err := expr.Run(program, &env)
+			if err != nil {
+				slog.Error("could not evaluate expression", slog.Any("err", err))
+				continue
+			}
+
+			*fields[i].target(&display) = output.(string)
+		}
+
+		app.display = display
+	}
+
+	return nil
+}
+
+// appEnv is the environment exposed to expressions evaluated against an app.
+type appEnv struct {
+	Name            string            `expr:"name"`
+	Namespace       string            `expr:"namespace"`
+	Kind            string            `expr:"kind"`
+	Annotations     map[string]string `expr:"annotations"`
+	Labels          map[string]string `expr:"labels"`
+	NamespaceLabels map[string]string `expr:"namespaceLabels"`
+	Replicas        int32             `expr:"replicas"`
+	ReadyReplicas   int32             `expr:"readyReplicas"`
+	Ready           bool              `expr:"ready"`
+	Maintenance     bool              `expr:"maintenance"`
+	Url             string            `expr:"url"`
+	Host            string            `expr:"host"`
+	IngressClass    string            `expr:"ingressClass"`
+	Services        []string          `expr:"services"`
+	Ports           []int             `expr:"ports"`
+	Images          []string          `expr:"images"`
+	Tags            []string          `expr:"tags"`
+	Version         string            `expr:"version"`
+	Dependencies    []string          `expr:"dependencies"`
+}
+
+func newAppEnv(app *App) appEnv {
+	status := app.Workload.GetStatus()
+	appUrl := app.Url()
+
+	env := appEnv{
+		Name:          app.Workload.GetName(),
+		Namespace:     app.Workload.GetNamespace(),
+		Kind:          app.Workload.GetKind(),
+		Annotations:   app.Annotations,
+		Labels:        app.Workload.GetLabels(),
+		Replicas:      status.Replicas,
+		ReadyReplicas: status.ReadyReplicas,
+		Ready:         app.Ready(),
+		Maintenance:   app.Maintenance() != nil,
+		Url:           appUrl,
+		IngressClass:  appIngressClass(app),
+		Services:      lo.Map(app.Services, func(service api.Service, _ int) string { return service.GetName() }),
+		Ports:         appPorts(app),
+		Images:        workloadImages(app.Workload),
+		Tags:          lo.Map(app.Workload.GetImages(), func(image Image, _ int) string { return image.Tag }),
+		Version:       app.Workload.GetVersion(),
+		Dependencies:  lo.Map(app.Dependencies, func(dependency Workload, _ int) string { return dependency.GetName() }),
+	}
+
+	if app.Namespace != nil {
+		env.NamespaceLabels = app.Namespace.GetLabels()
+	}
+
+	if parsed, err := url.Parse(appUrl); err == nil {
+		env.Host = parsed.Hostname()
+	}
+
+	return env
+}
+
+func appIngressClass(app *App) string {
+	if app.Ingress == nil {
+		return ""
+	}
+
+	if className := app.Ingress.Spec.IngressClassName; className != nil {
+		return *className
+	}
+
+	return app.Ingress.GetAnnotations()[aIngressClass]
+}
+
+// appPorts returns the distinct ports of all services of the app.
+func appPorts(app *App) []int {
+	var ports []int
+
+	for _, service := range app.Services {
+		for _, port := range service.Spec.Ports {
+			ports = append(ports, int(port.Port))
+		}
+	}
+
+	return lo.Uniq(ports)
+}
+
+func workloadImages(workload Workload) []string {
+	return lo.Map(workload.GetImages(), func(image Image, _ int) string { return image.String() })
+}
@@ -0,0 +1,201 @@
+package k8s
+
+import (
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/lukasdietrich/glance-k8s/internal/k8s/api"
+)
+
+const defaultStuckAfter = 15 * time.Minute
+
+type HelmOptions struct {
+	Namespace []string
+	// StuckAfter is the duration after which a pending release is flagged as
+	// stuck, e.g. "30m".
+	StuckAfter string
+}
+
+// HelmRelease is the latest revision of a Helm release.
+type HelmRelease struct {
+	Name         string
+	Namespace    string
+	Chart        string
+	ChartVersion string
+	AppVersion   string
+	Icon         string
+	Revision     int
+	Status       string
+	Description  string
+	LastDeployed time.Time
+	Stuck        bool
+}
+
+// Deployed reports whether the release was deployed successfully.
+func (r HelmRelease) Deployed() bool {
+	return r.Status == "deployed"
+}
+
+// Failed reports whether the last operation on the release failed.
+func (r HelmRelease) Failed() bool {
+	return r.Status == "failed"
+}
+
+// Pending reports whether an install, upgrade or rollback is in progress.
+func (r HelmRelease) Pending() bool {
+	return strings.HasPrefix(r.Status, "pending-")
+}
+
+// helmRelease is the subset of a release stored by Helm, which is needed to
+// display it. See helm.sh/helm/v3/pkg/release for the full schema.
+type helmRelease struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Version   int    `json:"version"`
+
+	Info struct {
+		Status       string    `json:"status"`
+		Description  string    `json:"description"`
+		LastDeployed time.Time `json:"last_deployed"`
+	} `json:"info"`
+
+	Chart struct {
+		Metadata struct {
+			Name       string `json:"name"`
+			Version    string `json:"version"`
+			AppVersion string `json:"appVersion"`
+			Icon       string `json:"icon"`
+		} `json:"metadata"`
+	} `json:"chart"`
+}
+
+// HelmReleases returns the latest revision of all Helm releases.
+func (c *Cluster) HelmReleases(ctx context.Context, opts HelmOptions) ([]HelmRelease, error) {
+	stuckAfter := defaultStuckAfter
+
+	if opts.StuckAfter != "" {
+		var err error
+
+		stuckAfter, err = time.ParseDuration(opts.StuckAfter)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse stuck-after: %w", err)
+		}
+	}
+
+	secrets, err := c.client.HelmReleaseSecrets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch helm releases: %w", err)
+	}
+
+	secrets = lo.Filter(secrets, func(secret api.Secret, _ int) bool {
+		return len(opts.Namespace) == 0 || slices.Contains(opts.Namespace, secret.Namespace)
+	})
+
+	var releases []HelmRelease
+
+	for _, secret := range latestHelmRevisions(secrets) {
+		release, err := decodeHelmRelease(secret.Data["release"])
+		if err != nil {
+			slog.Warn("could not decode helm release",
+				slog.String("secret", resourceFullname(&secret)),
+				slog.Any("err", err),
+			)
+
+			continue
+		}
+
+		release.Stuck = release.Pending() && time.Since(release.LastDeployed) > stuckAfter
+		releases = append(releases, release)
+	}
+
+	slices.SortFunc(releases, func(a, b HelmRelease) int {
+		return cmp.Or(
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+
+	return releases, nil
+}
+
+// latestHelmRevisions returns the secret of the latest revision of every
+// release. Revisions are compared by their labels, so only the latest
+// revision needs to be decoded.
+func latestHelmRevisions(secrets []api.Secret) []api.Secret {
+	latest := make(map[string]api.Secret)
+	revisions := make(map[string]int)
+
+	for _, secret := range secrets {
+		key := secret.Namespace + "/" + secret.Labels["name"]
+
+		revision, err := strconv.Atoi(secret.Labels["version"])
+		if err != nil {
+			continue
+		}
+
+		if _, ok := latest[key]; !ok || revision > revisions[key] {
+			latest[key] = secret
+			revisions[key] = revision
+		}
+	}
+
+	return lo.Values(latest)
+}
+
+// decodeHelmRelease decodes a release as stored by Helm, which is base64
+// encoded and usually gzipped JSON.
+func decodeHelmRelease(data []byte) (HelmRelease, error) {
+	decoded := make([]byte, base64.StdEncoding.DecodedLen(len(data)))
+
+	n, err := base64.StdEncoding.Decode(decoded, data)
+	if err != nil {
+		return HelmRelease{}, fmt.Errorf("could not decode base64: %w", err)
+	}
+
+	decoded = decoded[:n]
+
+	if bytes.HasPrefix(decoded, []byte{0x1f, 0x8b}) {
+		reader, err := gzip.NewReader(bytes.NewReader(decoded))
+		if err != nil {
+			return HelmRelease{}, fmt.Errorf("could not decompress: %w", err)
+		}
+
+		decoded, err = io.ReadAll(reader)
+		if err != nil {
+			return HelmRelease{}, fmt.Errorf("could not decompress: %w", err)
+		}
+	}
+
+	var release helmRelease
+	if err := json.Unmarshal(decoded, &release); err != nil {
+		return HelmRelease{}, fmt.Errorf("could not parse release: %w", err)
+	}
+
+	metadata := release.Chart.Metadata
+
+	return HelmRelease{
+		Name:         release.Name,
+		Namespace:    release.Namespace,
+		Chart:        metadata.Name,
+		ChartVersion: metadata.Version,
+		AppVersion:   metadata.AppVersion,
+		Icon:         metadata.Icon,
+		Revision:     release.Version,
+		Status:       release.Info.Status,
+		Description:  release.Info.Description,
+		LastDeployed: release.Info.LastDeployed,
+	}, nil
+}
@@ -0,0 +1,96 @@
+package k8s
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/lukasdietrich/glance-k8s/internal/k8s/api"
+)
+
+const testHelmRelease = `{
+	"name": "traefik",
+	"namespace": "infra",
+	"version": 3,
+	"info": {"status": "pending-upgrade", "last_deployed": "2024-05-01T10:00:00.123456+02:00"},
+	"chart": {"metadata": {"name": "traefik", "version": "30.1.0", "appVersion": "v3.1.2"}, "templates": []}
+}`
+
+// encodeHelmRelease encodes a release the way Helm stores it in a secret.
+func encodeHelmRelease(t *testing.T, release string, compress bool) []byte {
+	t.Helper()
+
+	data := []byte(release)
+
+	if compress {
+		var buf bytes.Buffer
+
+		writer := gzip.NewWriter(&buf)
+		if _, err := writer.Write(data); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		data = buf.Bytes()
+	}
+
+	return []byte(base64.StdEncoding.EncodeToString(data))
+}
+
+func TestDecodeHelmRelease(t *testing.T) {
+	for _, compress := range []bool{true, false} {
+		release, err := decodeHelmRelease(encodeHelmRelease(t, testHelmRelease, compress))
+		if err != nil {
+			t.Fatalf("could not decode: %v", err)
+		}
+
+		if release.Name != "traefik" || release.Namespace != "infra" || release.Revision != 3 {
+			t.Errorf("release = %+v, want traefik revision 3", release)
+		}
+
+		if release.Chart != "traefik" || release.ChartVersion != "30.1.0" || release.AppVersion != "v3.1.2" {
+			t.Errorf("chart = %+v, want traefik-30.1.0 of v3.1.2", release)
+		}
+
+		if !release.Pending() || !release.LastDeployed.Equal(time.Date(2024, 5, 1, 8, 0, 0, 123456000, time.UTC)) {
+			t.Errorf("info = %+v, want pending since 2024-05-01", release)
+		}
+	}
+
+	if _, err := decodeHelmRelease([]byte("not base64!")); err == nil {
+		t.Error("expected error for invalid data")
+	}
+}
+
+func TestLatestHelmRevisions(t *testing.T) {
+	secret := func(namespace, name, version string) api.Secret {
+		return api.Secret{ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      "sh.helm.release.v1." + name + ".v" + version,
+			Labels:    map[string]string{"name": name, "version": version},
+		}}
+	}
+
+	latest := latestHelmRevisions([]api.Secret{
+		secret("infra", "traefik", "9"),
+		secret("infra", "traefik", "10"),
+		secret("infra", "traefik", "2"),
+		secret("web", "traefik", "1"),
+	})
+
+	names := make(map[string]bool)
+	for _, secret := range latest {
+		names[resourceFullname(&secret)] = true
+	}
+
+	if len(names) != 2 || !names["infra/sh.helm.release.v1.traefik.v10"] || !names["web/sh.helm.release.v1.traefik.v1"] {
+		t.Errorf("latest = %v, want revision 10 in infra and 1 in web", names)
+	}
+}
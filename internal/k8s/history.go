@@ -0,0 +1,316 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// historyRetention is how long transitions are kept.
+	historyRetention = 7 * 24 * time.Hour
+	// historyTimelineBuckets is the number of hourly buckets of a timeline.
+	historyTimelineBuckets = 24
+)
+
+type subjectState string
+
+const (
+	stateUp      subjectState = "up"
+	stateDown    subjectState = "down"
+	stateUnknown subjectState = "unknown"
+	// stateMaintenance marks an intentional downtime, which does not count
+	// towards the uptime and is not notified.
+	stateMaintenance subjectState = "maintenance"
+)
+
+// History summarizes the recorded readiness of an app or node.
+type History struct {
+	// Up is true, if the subject was ready when last sampled.
+	Up bool
+	// Down is true, if the subject was unready when last sampled. Both are
+	// false, if it was in maintenance or its state was unknown.
+	Down bool
+	// Since is the time of the last state change.
+	Since time.Time
+	// LastIncident is the time the subject last became unready.
+	LastIncident time.Time
+	// Uptime24h and Uptime7d are ratios between 0 and 1, or -1 if unknown.
+	Uptime24h float64
+	Uptime7d  float64
+	// Timeline holds the uptime ratio of each hour of the last day, oldest
+	// first, or -1 if unknown.
+	Timeline []float64
+}
+
+type transition struct {
+	Time  time.Time    `json:"time"`
+	State subjectState `json:"state"`
+}
+
+type subjectHistory struct {
+	Transitions []transition `json:"transitions"`
+	LastSeen    time.Time    `json:"lastSeen"`
+}
+
+// history samples the readiness of apps and nodes and records transitions
+// between states. When filename is set, the history is persisted as JSON
+// after every sample and restored on startup.
+type history struct {
+	interval time.Duration
+	filename string
+
+	mu       sync.RWMutex
+	subjects map[string]*subjectHistory
+}
+
+// newHistory creates a history from the GLANCE_HISTORY_INTERVAL duration and
+// the optional GLANCE_HISTORY_FILE. An empty interval disables the history.
+func newHistory(interval, filename string) (*history, error) {
+	if interval == "" {
+		return nil, nil
+	}
+
+	duration, err := time.ParseDuration(interval)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse history interval: %w", err)
+	}
+
+	if duration <= 0 {
+		return nil, fmt.Errorf("could not parse history interval: %q is not positive", interval)
+	}
+
+	h := &history{
+		interval: duration,
+		filename: filename,
+		subjects: make(map[string]*subjectHistory),
+	}
+
+	if err := h.load(); err != nil {
+		return nil, fmt.Errorf("could not load history: %w", err)
+	}
+
+	return h, nil
+}
+
+// run records the states returned by sample once per interval until ctx is
+// done.
+func (h *history) run(ctx context.Context, sample func(context.Context) (map[string]subjectSample, error)) {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		if subjects, err := sample(ctx); err != nil {
+			slog.Warn("could not sample states", slog.Any("err", err))
+		} else {
+			states := make(map[string]subjectState, len(subjects))
+			for key, subject := range subjects {
+				states[key] = subject.State
+			}
+
+			h.recordAll(time.Now(), states)
+
+			if err := h.save(); err != nil {
+				slog.Warn("could not save history", slog.Any("err", err))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *history) recordAll(now time.Time, states map[string]subjectState) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for key, state := range states {
+		subject, ok := h.subjects[key]
+		if !ok {
+			subject = &subjectHistory{}
+			h.subjects[key] = subject
+		}
+
+		subject.record(now, state, h.interval)
+	}
+
+	cutoff := now.Add(-historyRetention)
+	for key, subject := range h.subjects {
+		if subject.LastSeen.Before(cutoff) {
+			delete(h.subjects, key)
+		} else {
+			subject.prune(cutoff)
+		}
+	}
+}
+
+func (s *subjectHistory) record(now time.Time, state subjectState, interval time.Duration) {
+	if n := len(s.Transitions); n > 0 {
+		// Samples were missed, e.g. while glance-k8s was not running.
+		if now.Sub(s.LastSeen) > 2*interval && s.Transitions[n-1].State != stateUnknown {
+			s.Transitions = append(s.Transitions, transition{Time: s.LastSeen, State: stateUnknown})
+		}
+	}
+
+	if n := len(s.Transitions); n == 0 || s.Transitions[n-1].State != state {
+		s.Transitions = append(s.Transitions, transition{Time: now, State: state})
+	}
+
+	s.LastSeen = now
+}
+
+// prune drops transitions before cutoff, but keeps the last one of them to
+// retain the state at the cutoff.
+func (s *subjectHistory) prune(cutoff time.Time) {
+	var i int
+	for i < len(s.Transitions)-1 && !s.Transitions[i+1].Time.After(cutoff) {
+		i++
+	}
+
+	s.Transitions = s.Transitions[i:]
+}
+
+// durations sums the time spent in each state between from and to.
+func (s *subjectHistory) durations(from, to, end time.Time) map[subjectState]time.Duration {
+	durations := make(map[subjectState]time.Duration)
+
+	for i, t := range s.Transitions {
+		segmentEnd := end
+		if i+1 < len(s.Transitions) {
+			segmentEnd = s.Transitions[i+1].Time
+		}
+
+		start, stop := maxTime(t.Time, from), minTime(segmentEnd, to)
+		if stop.After(start) {
+			durations[t.State] += stop.Sub(start)
+		}
+	}
+
+	return durations
+}
+
+func (h *history) summary(key string, now time.Time) (*History, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	subject, ok := h.subjects[key]
+	if !ok || len(subject.Transitions) == 0 {
+		return nil, false
+	}
+
+	// The last known state lasts until now, unless samples are missing.
+	end := now
+	if now.Sub(subject.LastSeen) > 2*h.interval {
+		end = subject.LastSeen
+	}
+
+	uptime := func(from, to time.Time) float64 {
+		durations := subject.durations(from, to, end)
+		up, down := durations[stateUp], durations[stateDown]
+
+		if up+down == 0 {
+			return -1
+		}
+
+		return float64(up) / float64(up+down)
+	}
+
+	last := subject.Transitions[len(subject.Transitions)-1]
+	summary := History{
+		Up:        last.State == stateUp,
+		Down:      last.State == stateDown,
+		Since:     last.Time,
+		Uptime24h: uptime(now.Add(-24*time.Hour), now),
+		Uptime7d:  uptime(now.Add(-7*24*time.Hour), now),
+		Timeline:  make([]float64, historyTimelineBuckets),
+	}
+
+	for _, t := range subject.Transitions {
+		if t.State == stateDown {
+			summary.LastIncident = t.Time
+		}
+	}
+
+	for i := range summary.Timeline {
+		from := now.Add(-time.Duration(historyTimelineBuckets-i) * time.Hour)
+		summary.Timeline[i] = uptime(from, from.Add(time.Hour))
+	}
+
+	return &summary, true
+}
+
+func (h *history) load() error {
+	if h.filename == "" {
+		return nil
+	}
+
+	content, err := os.ReadFile(h.filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(content, &h.subjects)
+}
+
+func (h *history) save() error {
+	if h.filename == "" {
+		return nil
+	}
+
+	h.mu.RLock()
+	content, err := json.Marshal(h.subjects)
+	h.mu.RUnlock()
+
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, so a crash does not leave a
+	// truncated history behind.
+	temp, err := os.CreateTemp(filepath.Dir(h.filename), ".history-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(content); err != nil {
+		_ = temp.Close()
+		return err
+	}
+
+	if err := temp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(temp.Name(), h.filename)
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,122 @@
+package k8s
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewHistory_NonPositiveInterval(t *testing.T) {
+	for _, interval := range []string{"0s", "-1m"} {
+		if _, err := newHistory(interval, ""); err == nil {
+			t.Errorf("expected error for interval %q", interval)
+		}
+	}
+}
+
+func TestHistory_Summary(t *testing.T) {
+	h, err := newHistory("1m", "")
+	if err != nil {
+		t.Fatalf("could not create history: %v", err)
+	}
+
+	start := time.Now().Add(-4 * time.Hour)
+
+	// up for 3h, then down for 1h
+	for minute := 0; minute <= 4*60; minute++ {
+		state := stateUp
+		if minute > 3*60 {
+			state = stateDown
+		}
+
+		h.recordAll(start.Add(time.Duration(minute)*time.Minute), map[string]subjectState{"apps/a/web": state})
+	}
+
+	now := start.Add(4 * time.Hour)
+	summary, ok := h.summary("apps/a/web", now)
+	if !ok {
+		t.Fatalf("no summary")
+	}
+
+	if summary.Up || !summary.Down {
+		t.Fatalf("expected subject to be down")
+	}
+
+	if got, want := summary.Since, start.Add(181*time.Minute); !got.Equal(want) {
+		t.Fatalf("since = %v, want %v", got, want)
+	}
+
+	if got := summary.Uptime24h; got < 0.74 || got > 0.76 {
+		t.Fatalf("uptime 24h = %v, want ~0.75", got)
+	}
+
+	if got := summary.Timeline[0]; got != -1 {
+		t.Fatalf("oldest bucket = %v, want unknown", got)
+	}
+
+	if got := summary.Timeline[len(summary.Timeline)-1]; got > 0.05 {
+		t.Fatalf("newest bucket = %v, want ~0", got)
+	}
+}
+
+func TestHistory_GapIsUnknown(t *testing.T) {
+	h, err := newHistory("1m", "")
+	if err != nil {
+		t.Fatalf("could not create history: %v", err)
+	}
+
+	start := time.Now().Add(-2 * time.Hour)
+	h.recordAll(start, map[string]subjectState{"nodes/a": stateDown})
+
+	// no samples for an hour, then up for an hour
+	for minute := 60; minute <= 120; minute++ {
+		h.recordAll(start.Add(time.Duration(minute)*time.Minute), map[string]subjectState{"nodes/a": stateUp})
+	}
+
+	summary, _ := h.summary("nodes/a", start.Add(2*time.Hour))
+	if got := summary.Uptime24h; got != 1 {
+		t.Fatalf("uptime 24h = %v, want 1", got)
+	}
+
+	if got := summary.Timeline[len(summary.Timeline)-2]; got != -1 {
+		t.Fatalf("bucket of gap = %v, want unknown", got)
+	}
+}
+
+func TestHistory_Persistence(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "history.json")
+
+	h, err := newHistory("1m", filename)
+	if err != nil {
+		t.Fatalf("could not create history: %v", err)
+	}
+
+	now := time.Now()
+	h.recordAll(now, map[string]subjectState{"apps/a/web": stateUp})
+
+	if err := h.save(); err != nil {
+		t.Fatalf("could not save history: %v", err)
+	}
+
+	restored, err := newHistory("1m", filename)
+	if err != nil {
+		t.Fatalf("could not restore history: %v", err)
+	}
+
+	if summary, ok := restored.summary("apps/a/web", now); !ok || !summary.Up {
+		t.Fatalf("history was not restored")
+	}
+}
+
+func TestHistorySummary_MaintenanceIsNotDown(t *testing.T) {
+	h := &history{interval: time.Minute, subjects: make(map[string]*subjectHistory)}
+	now := time.Now()
+
+	h.recordAll(now.Add(-time.Minute), map[string]subjectState{"apps/a/web": stateUp})
+	h.recordAll(now, map[string]subjectState{"apps/a/web": stateMaintenance})
+
+	summary, ok := h.summary("apps/a/web", now)
+	if !ok || summary.Up || summary.Down {
+		t.Fatalf("summary = %+v, want neither up nor down", summary)
+	}
+}
@@ -0,0 +1,167 @@
+package k8s
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// builtinImageIcons maps container image repositories to icons. Keys are
+// matched against the full repository, the repository without registry
+// and the last path segment, in that order.
+var builtinImageIcons = map[string]string{
+	"adguardhome":             "di:adguard-home",
+	"audiobookshelf":          "di:audiobookshelf",
+	"authelia":                "di:authelia",
+	"goauthentik/server":      "di:authentik",
+	"bazarr":                  "di:bazarr",
+	"code-server":             "di:code-server",
+	"esphome":                 "di:esphome",
+	"excalidraw":              "di:excalidraw",
+	"freshrss":                "di:freshrss",
+	"gitea":                   "di:gitea",
+	"glance":                  "di:glance",
+	"gotify/server":           "di:gotify",
+	"grafana":                 "di:grafana",
+	"home-assistant":          "di:home-assistant",
+	"homeassistant":           "di:home-assistant",
+	"immich-machine-learning": "di:immich",
+	"immich-server":           "di:immich",
+	"jellyfin":                "di:jellyfin",
+	"jellyseerr":              "di:jellyseerr",
+	"keycloak":                "di:keycloak",
+	"lidarr":                  "di:lidarr",
+	"mariadb":                 "di:mariadb",
+	"mealie":                  "di:mealie",
+	"minio":                   "di:minio",
+	"mongo":                   "di:mongodb",
+	"mosquitto":               "di:mosquitto",
+	"mysql":                   "di:mysql",
+	"n8n":                     "di:n8n",
+	"navidrome":               "di:navidrome",
+	"nextcloud":               "di:nextcloud",
+	"nginx":                   "di:nginx",
+	"node-red":                "di:node-red",
+	"ntfy":                    "di:ntfy",
+	"paperless-ngx":           "di:paperless-ngx",
+	"pihole":                  "di:pi-hole",
+	"plex":                    "di:plex",
+	"postgres":                "di:postgres",
+	"prometheus":              "di:prometheus",
+	"prowlarr":                "di:prowlarr",
+	"qbittorrent":             "di:qbittorrent",
+	"radarr":                  "di:radarr",
+	"redis":                   "di:redis",
+	"sabnzbd":                 "di:sabnzbd",
+	"sonarr":                  "di:sonarr",
+	"syncthing":               "di:syncthing",
+	"traefik":                 "di:traefik",
+	"uptime-kuma":             "di:uptime-kuma",
+	"vaultwarden/server":      "di:vaultwarden",
+	"zigbee2mqtt":             "di:zigbee2mqtt",
+}
+
+// imageIcons resolves icons for container images.
+type imageIcons map[string]string
+
+// loadImageIcons merges the built-in mapping with the YAML mapping file at
+// filename. Entries of the file take precedence. An empty filename only
+// uses the built-in mapping.
+func loadImageIcons(filename string) (imageIcons, error) {
+	icons := maps.Clone(builtinImageIcons)
+
+	if filename == "" {
+		return icons, nil
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("could not read icon mapping: %w", err)
+	}
+
+	var custom map[string]string
+	if err := yaml.Unmarshal(content, &custom); err != nil {
+		return nil, fmt.Errorf("could not parse icon mapping: %w", err)
+	}
+
+	for image, icon := range custom {
+		icons[strings.ToLower(image)] = icon
+	}
+
+	return icons, nil
+}
+
+// forWorkload returns the icon of the main container image of the workload
+// found in the mapping. Sidecars are ignored, so they cannot set the icon.
+func (i imageIcons) forWorkload(workload Workload) (string, bool) {
+	image, ok := mainImage(workload)
+	if !ok {
+		return "", false
+	}
+
+	for _, candidate := range imageCandidates(image.String()) {
+		if icon, ok := i[candidate]; ok {
+			return icon, true
+		}
+	}
+
+	return "", false
+}
+
+// mainImage returns the image of the container named after the workload,
+// falling back to the first container.
+func mainImage(workload Workload) (Image, bool) {
+	images := workload.GetImages()
+	if len(images) == 0 {
+		return Image{}, false
+	}
+
+	for _, image := range images {
+		if image.Container == workload.GetName() {
+			return image, true
+		}
+	}
+
+	return images[0], true
+}
+
+// imageCandidates returns the lookup keys of an image from most to least
+// specific, e.g. "ghcr.io/immich-app/immich-server",
+// "immich-app/immich-server" and "immich-server".
+func imageCandidates(image string) []string {
+	repository := strings.ToLower(image)
+
+	if i := strings.Index(repository, "@"); i >= 0 {
+		repository = repository[:i]
+	}
+
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository = repository[:i]
+	}
+
+	candidates := []string{repository}
+
+	segments := strings.Split(repository, "/")
+	if len(segments) > 1 && isImageRegistry(segments[0]) {
+		segments = segments[1:]
+		candidates = append(candidates, strings.Join(segments, "/"))
+	}
+
+	if len(segments) > 1 && segments[0] == "library" {
+		segments = segments[1:]
+		candidates = append(candidates, strings.Join(segments, "/"))
+	}
+
+	if len(segments) > 1 {
+		candidates = append(candidates, segments[len(segments)-1])
+	}
+
+	return candidates
+}
+
+func isImageRegistry(segment string) bool {
+	return strings.ContainsAny(segment, ".:") || segment == "localhost"
+}
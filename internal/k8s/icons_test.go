@@ -0,0 +1,79 @@
+package k8s
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/lukasdietrich/glance-k8s/internal/k8s/api"
+)
+
+func TestImageCandidates(t *testing.T) {
+	tests := map[string][]string{
+		"ghcr.io/immich-app/immich-server:v1.120.0": {"ghcr.io/immich-app/immich-server", "immich-app/immich-server", "immich-server"},
+		"docker.io/library/redis:7@sha256:abc":      {"docker.io/library/redis", "library/redis", "redis"},
+		"localhost:5000/app":                        {"localhost:5000/app", "app"},
+		"Jellyfin/Jellyfin":                         {"jellyfin/jellyfin", "jellyfin"},
+		"nginx":                                     {"nginx"},
+	}
+
+	for image, want := range tests {
+		if got := imageCandidates(image); !slices.Equal(got, want) {
+			t.Errorf("imageCandidates(%q) = %v, want %v", image, got, want)
+		}
+	}
+}
+
+func TestImageIcons_CustomMappingTakesPrecedence(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "icons.yaml")
+	mapping := "ghcr.io/immich-app/immich-server: https://example.org/immich.png\nexample/app: si:example\n"
+	if err := os.WriteFile(filename, []byte(mapping), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	icons, err := loadImageIcons(filename)
+	if err != nil {
+		t.Fatalf("could not load icons: %v", err)
+	}
+
+	tests := map[string]string{
+		"ghcr.io/immich-app/immich-server:release": "https://example.org/immich.png",
+		"ghcr.io/example/app:latest":               "si:example",
+		"lscr.io/linuxserver/sonarr:latest":        "di:sonarr",
+	}
+
+	for image, want := range tests {
+		workload := testDeployment("a", "app", nil, nil).(*deployment)
+		workload.Spec.Template.Spec.Containers = []api.Container{{Image: image}}
+
+		if got, ok := icons.forWorkload(workload); !ok || got != want {
+			t.Errorf("forWorkload(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
+
+func TestImageIcons_MainContainerOnly(t *testing.T) {
+	icons, err := loadImageIcons("")
+	if err != nil {
+		t.Fatalf("could not load icons: %v", err)
+	}
+
+	tests := []struct {
+		containers []api.Container
+		want       string
+	}{
+		{[]api.Container{{Name: "server", Image: "ghcr.io/goauthentik/server:2024.8"}, {Name: "proxy", Image: "nginx"}}, "di:authentik"},
+		{[]api.Container{{Name: "vpn", Image: "qmcgaw/gluetun"}, {Name: "app", Image: "lscr.io/linuxserver/qbittorrent"}}, "di:qbittorrent"},
+		{[]api.Container{{Name: "unknown", Image: "example/unknown"}, {Name: "cache", Image: "redis"}}, ""},
+	}
+
+	for _, test := range tests {
+		workload := testDeployment("a", "app", nil, nil).(*deployment)
+		workload.Spec.Template.Spec.Containers = test.containers
+
+		if got, _ := icons.forWorkload(workload); got != test.want {
+			t.Errorf("forWorkload(%v) = %q, want %q", test.containers, got, test.want)
+		}
+	}
+}
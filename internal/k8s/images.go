@@ -0,0 +1,80 @@
+package k8s
+
+import (
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/lukasdietrich/glance-k8s/internal/k8s/api"
+)
+
+// Image is a container image reference of a workload.
+type Image struct {
+	Container  string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// parseImage splits an image reference like "ghcr.io/app/app:v1@sha256:abc"
+// into repository, tag and digest. The tag defaults to "latest", unless the
+// image is pinned by digest only.
+func parseImage(container, reference string) Image {
+	image := Image{Container: container, Repository: reference}
+
+	if repository, digest, ok := strings.Cut(image.Repository, "@"); ok {
+		image.Repository, image.Digest = repository, digest
+	}
+
+	if i := strings.LastIndex(image.Repository, ":"); i > strings.LastIndex(image.Repository, "/") {
+		image.Repository, image.Tag = image.Repository[:i], image.Repository[i+1:]
+	}
+
+	if image.Tag == "" && image.Digest == "" {
+		image.Tag = "latest"
+	}
+
+	return image
+}
+
+// ShortDigest returns the digest truncated to 12 hex characters.
+func (i Image) ShortDigest() string {
+	_, hash, ok := strings.Cut(i.Digest, ":")
+	if !ok || len(hash) <= 12 {
+		return i.Digest
+	}
+
+	return hash[:12]
+}
+
+func (i Image) String() string {
+	reference := i.Repository
+
+	if i.Tag != "" {
+		reference += ":" + i.Tag
+	}
+
+	if i.Digest != "" {
+		reference += "@" + i.Digest
+	}
+
+	return reference
+}
+
+// templateImages returns the images of all containers of a pod template,
+// excluding init containers.
+func templateImages(template api.PodTemplateSpec) []Image {
+	return lo.Map(template.Spec.Containers, func(container api.Container, _ int) Image {
+		return parseImage(container.Name, container.Image)
+	})
+}
+
+// templateVersion returns the version label of a workload, falling back to
+// the version label of its pod template.
+func templateVersion(labels map[string]string, template api.PodTemplateSpec) string {
+	if version, ok := labels[lVersion]; ok {
+		return version
+	}
+
+	return template.Labels[lVersion]
+}
@@ -0,0 +1,29 @@
+package k8s
+
+import (
+	"testing"
+)
+
+func TestParseImage(t *testing.T) {
+	tests := map[string]Image{
+		"nginx":                               {Repository: "nginx", Tag: "latest"},
+		"localhost:5000/app:v1":               {Repository: "localhost:5000/app", Tag: "v1"},
+		"ghcr.io/a/b:1.2@sha256:abcdef":       {Repository: "ghcr.io/a/b", Tag: "1.2", Digest: "sha256:abcdef"},
+		"ghcr.io/a/b@sha256:0123456789abcdef": {Repository: "ghcr.io/a/b", Digest: "sha256:0123456789abcdef"},
+	}
+
+	for reference, want := range tests {
+		got := parseImage("", reference)
+		if got != want {
+			t.Errorf("parseImage(%q) = %+v, want %+v", reference, got, want)
+		}
+
+		if want.Tag != "latest" && got.String() != reference {
+			t.Errorf("%+v.String() = %q, want %q", got, got.String(), reference)
+		}
+	}
+
+	if got := parseImage("", "a@sha256:0123456789abcdef").ShortDigest(); got != "0123456789ab" {
+		t.Errorf("short digest = %q, want %q", got, "0123456789ab")
+	}
+}
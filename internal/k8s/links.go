@@ -0,0 +1,98 @@
+package k8s
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	aLinks      = "glance/links"
+	aLinkPrefix = "glance/link."
+	// aLinkIconSuffix marks the icon of a titled link, e.g.
+	// glance/link.admin-ui.icon.
+	aLinkIconSuffix = ".icon"
+)
+
+// linksCache parses glance/links once per annotation value, so an invalid
+// value is only logged once instead of on every request.
+var linksCache = newCompileCache(compileCacheSize, compileLinks).publish("cache.links")
+
+// Link is a secondary link of an app, e.g. to an admin interface or docs.
+type Link struct {
+	Title string `json:"title"`
+	Url   string `json:"url"`
+	Icon  string `json:"icon"`
+}
+
+// parseLinks collects the links of an app. glance/links holds a YAML list
+// of links and comes first, followed by glance/link.<title> annotations
+// sorted by title, each with an optional glance/link.<title>.icon. An
+// invalid glance/links annotation is reported, but the titled links are
+// still returned.
+func parseLinks(annotations map[string]string) ([]Link, error) {
+	var (
+		links []Link
+		err   error
+	)
+
+	if value, ok := annotations[aLinks]; ok {
+		links, err = linksCache.get(value)
+		links = slices.Clone(links)
+	}
+
+	var titled []Link
+
+	for key, value := range annotations {
+		name, ok := strings.CutPrefix(key, aLinkPrefix)
+		if !ok || name == "" || value == "" || strings.HasSuffix(name, aLinkIconSuffix) {
+			continue
+		}
+
+		titled = append(titled, Link{
+			Title: cases.Title(language.English).String(strings.ReplaceAll(name, "-", " ")),
+			Url:   value,
+			Icon:  annotations[aLinkPrefix+name+aLinkIconSuffix],
+		})
+	}
+
+	sort.Slice(titled, func(i, j int) bool {
+		return titled[i].Title < titled[j].Title
+	})
+
+	return append(links, titled...), err
+}
+
+func compileLinks(value string) ([]Link, error) {
+	links, err := parseLinkList(value)
+	if err != nil {
+		slog.Warn("could not parse links", slog.Any("err", err))
+	}
+
+	return links, err
+}
+
+func parseLinkList(value string) ([]Link, error) {
+	var links []Link
+	if err := yaml.Unmarshal([]byte(value), &links); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", aLinks, err)
+	}
+
+	for i, link := range links {
+		if link.Url == "" {
+			return nil, fmt.Errorf("invalid %s annotation: link %d has no url", aLinks, i)
+		}
+
+		if link.Title == "" {
+			links[i].Title = link.Url
+		}
+	}
+
+	return links, nil
+}
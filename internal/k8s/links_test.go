@@ -0,0 +1,42 @@
+package k8s
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseLinks(t *testing.T) {
+	links, err := parseLinks(map[string]string{
+		aLinks:                        "- title: Docs\n  url: https://docs.example.org\n  icon: mdi:book\n- url: https://example.org/metrics",
+		aLinkPrefix + "admin-ui":      "https://example.org/admin",
+		aLinkPrefix + "admin-ui.icon": "mdi:shield",
+		aLinkPrefix + "api":           "https://example.org/api",
+	})
+	if err != nil {
+		t.Fatalf("could not parse links: %v", err)
+	}
+
+	want := []Link{
+		{Title: "Docs", Url: "https://docs.example.org", Icon: "mdi:book"},
+		{Title: "https://example.org/metrics", Url: "https://example.org/metrics"},
+		{Title: "Admin Ui", Url: "https://example.org/admin", Icon: "mdi:shield"},
+		{Title: "Api", Url: "https://example.org/api"},
+	}
+
+	if !slices.Equal(links, want) {
+		t.Fatalf("links = %v, want %v", links, want)
+	}
+
+	if _, err := parseLinks(map[string]string{aLinks: "- title: Docs"}); err == nil {
+		t.Fatal("expected an error for a link without url")
+	}
+
+	links, err = parseLinks(map[string]string{aLinks: "not: [valid", aLinkPrefix + "admin": "https://admin"})
+	if err == nil {
+		t.Fatal("expected an error for invalid yaml")
+	}
+
+	if want := []Link{{Title: "Admin", Url: "https://admin"}}; !slices.Equal(links, want) {
+		t.Fatalf("links = %v, want titled links to be kept", links)
+	}
+}
@@ -30,6 +30,11 @@ type Node struct {
 	api.ObjectMeta
 	Status  api.NodeStatus
 	Metrics api.NodeMetrics
+	History *History
+}
+
+func (n *Node) subjectKey() string {
+	return "nodes/" + n.Name
 }
 
 func (n *Node) ConditionTrue(conditionType api.NodeConditionType) bool {
@@ -92,6 +97,14 @@ func (c *Cluster) Nodes(ctx context.Context) (NodeSlice, error) {
 	}
 
 	nodes := NodeSlice(lo.Map(nodeInfos, wrapNodeWithMetrics(nodeMetrics)))
+
+	if c.history != nil {
+		now := time.Now()
+		for i := range nodes {
+			nodes[i].History, _ = c.history.summary(nodes[i].subjectKey(), now)
+		}
+	}
+
 	sort.Stable(nodes)
 	return nodes, nil
 }
@@ -0,0 +1,385 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Masterminds/sprig/v3"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	defaultNotifyInterval      = 30 * time.Second
+	defaultNotifyDebounce      = 2 * time.Minute
+	defaultNotifyFlapWindow    = 30 * time.Minute
+	defaultNotifyFlapThreshold = 4
+)
+
+// NotifyEvent describes a state change of an app or node. It is the data
+// of webhook body templates.
+type NotifyEvent struct {
+	Kind      string    `json:"kind"`
+	Name      string    `json:"name"`
+	Namespace string    `json:"namespace,omitempty"`
+	Url       string    `json:"url,omitempty"`
+	State     string    `json:"state"`
+	Previous  string    `json:"previous"`
+	Flapping  bool      `json:"flapping"`
+	Time      time.Time `json:"time"`
+}
+
+// Message is a human readable summary of the event.
+func (e NotifyEvent) Message() string {
+	switch {
+	case e.Flapping:
+		return fmt.Sprintf("%s is flapping, notifications are suppressed until it is stable", e.Name)
+	case e.State == string(stateUp):
+		return fmt.Sprintf("%s is up again", e.Name)
+	default:
+		return fmt.Sprintf("%s is down", e.Name)
+	}
+}
+
+type notifyConfig struct {
+	Interval      duration       `json:"interval"`
+	Debounce      duration       `json:"debounce"`
+	FlapWindow    duration       `json:"flapWindow"`
+	FlapThreshold int            `json:"flapThreshold"`
+	Targets       []notifyTarget `json:"targets"`
+}
+
+type notifyTarget struct {
+	Type    string            `json:"type"`
+	Url     string            `json:"url"`
+	Token   string            `json:"token"`
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body"`
+
+	body *template.Template
+}
+
+// duration unmarshals from a Go duration string like "30s".
+type duration time.Duration
+
+func (d *duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = duration(parsed)
+	return nil
+}
+
+// notifyState tracks a single subject for debouncing and flap detection.
+type notifyState struct {
+	confirmed    subjectState
+	pending      subjectState
+	pendingSince time.Time
+	transitions  []time.Time
+	flapping     bool
+	notified     subjectState
+}
+
+// notifier samples the readiness of apps and nodes and sends notifications
+// to all targets, once a changed state persisted for the debounce duration.
+// Subjects changing state more than flapThreshold times within flapWindow
+// are considered flapping and their notifications are suppressed.
+type notifier struct {
+	config notifyConfig
+	client *http.Client
+	states map[string]*notifyState
+}
+
+// newNotifier creates a notifier from the YAML config file at filename. An
+// empty filename disables notifications.
+func newNotifier(filename string) (*notifier, error) {
+	if filename == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("could not read notification config: %w", err)
+	}
+
+	config := notifyConfig{
+		Interval:      duration(defaultNotifyInterval),
+		Debounce:      duration(defaultNotifyDebounce),
+		FlapWindow:    duration(defaultNotifyFlapWindow),
+		FlapThreshold: defaultNotifyFlapThreshold,
+	}
+
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return nil, fmt.Errorf("could not parse notification config: %w", err)
+	}
+
+	return newNotifierFromConfig(config)
+}
+
+func newNotifierFromConfig(config notifyConfig) (*notifier, error) {
+	for _, field := range []struct {
+		name  string
+		value duration
+	}{
+		{"interval", config.Interval},
+		{"debounce", config.Debounce},
+		{"flapWindow", config.FlapWindow},
+	} {
+		if field.value <= 0 {
+			return nil, fmt.Errorf("%s must be positive, got %s", field.name, time.Duration(field.value))
+		}
+	}
+
+	for i := range config.Targets {
+		target := &config.Targets[i]
+
+		switch target.Type {
+		case "webhook":
+			if target.Body == "" {
+				continue
+			}
+
+			body, err := template.New("").Funcs(sprig.TxtFuncMap()).Parse(target.Body)
+			if err != nil {
+				return nil, fmt.Errorf("could not parse body of target %d: %w", i, err)
+			}
+
+			target.body = body
+		case "ntfy", "gotify":
+		default:
+			return nil, fmt.Errorf("unknown type %q of target %d", target.Type, i)
+		}
+	}
+
+	return &notifier{
+		config: config,
+		client: &http.Client{Timeout: 10 * time.Second},
+		states: make(map[string]*notifyState),
+	}, nil
+}
+
+// run samples once per interval until ctx is done.
+func (n *notifier) run(ctx context.Context, sample func(context.Context) (map[string]subjectSample, error)) {
+	ticker := time.NewTicker(time.Duration(n.config.Interval))
+	defer ticker.Stop()
+
+	for {
+		if subjects, err := sample(ctx); err != nil {
+			slog.Warn("could not sample states", slog.Any("err", err))
+		} else {
+			for _, event := range n.observe(time.Now(), subjects) {
+				n.notifyAll(ctx, event)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// observe updates the tracked states and returns the events to notify.
+func (n *notifier) observe(now time.Time, subjects map[string]subjectSample) []NotifyEvent {
+	var events []NotifyEvent
+
+	for key, subject := range subjects {
+		state, ok := n.states[key]
+		if !ok || subject.State == stateMaintenance {
+			n.states[key] = &notifyState{confirmed: subject.State, notified: subject.State}
+			continue
+		}
+
+		if state.confirmed == stateMaintenance {
+			// After a maintenance the subject is expected to be up again.
+			state = &notifyState{confirmed: stateUp, notified: stateUp}
+			n.states[key] = state
+		}
+
+		event := NotifyEvent{
+			Kind:      subject.Kind,
+			Name:      subject.Name,
+			Namespace: subject.Namespace,
+			Url:       subject.Url,
+			State:     string(subject.State),
+			Time:      now,
+		}
+
+		if subject.State == state.confirmed {
+			state.pending = ""
+		} else if subject.State != state.pending {
+			state.pending = subject.State
+			state.pendingSince = now
+		}
+
+		if state.pending != "" && now.Sub(state.pendingSince) >= time.Duration(n.config.Debounce) {
+			state.confirmed = state.pending
+			state.pending = ""
+			state.transitions = append(state.transitions, now)
+		}
+
+		state.transitions = dropBefore(state.transitions, now.Add(-time.Duration(n.config.FlapWindow)))
+		flapping := len(state.transitions) > n.config.FlapThreshold
+
+		switch {
+		case flapping && !state.flapping:
+			event.Flapping = true
+			event.State = string(state.confirmed)
+			event.Previous = string(state.notified)
+			events = append(events, event)
+		case !flapping && state.confirmed != state.notified:
+			event.State = string(state.confirmed)
+			event.Previous = string(state.notified)
+			events = append(events, event)
+			state.notified = state.confirmed
+		}
+
+		state.flapping = flapping
+	}
+
+	for key := range n.states {
+		if _, ok := subjects[key]; !ok {
+			delete(n.states, key)
+		}
+	}
+
+	return events
+}
+
+func dropBefore(times []time.Time, cutoff time.Time) []time.Time {
+	for len(times) > 0 && times[0].Before(cutoff) {
+		times = times[1:]
+	}
+
+	return times
+}
+
+func (n *notifier) notifyAll(ctx context.Context, event NotifyEvent) {
+	slog.Info("state changed",
+		slog.String("kind", event.Kind),
+		slog.String("name", event.Name),
+		slog.String("state", event.State),
+		slog.Bool("flapping", event.Flapping),
+	)
+
+	for _, target := range n.config.Targets {
+		if err := n.notify(ctx, target, event); err != nil {
+			slog.Error("could not send notification",
+				slog.String("type", target.Type),
+				slog.String("url", target.Url),
+				slog.Any("err", err),
+			)
+		}
+	}
+}
+
+func (n *notifier) notify(ctx context.Context, target notifyTarget, event NotifyEvent) error {
+	var (
+		url     = target.Url
+		body    []byte
+		headers = make(map[string]string)
+		err     error
+	)
+
+	up := event.State == string(stateUp) && !event.Flapping
+
+	switch target.Type {
+	case "webhook":
+		headers["Content-Type"] = "application/json"
+
+		if body, err = renderWebhookBody(target, event); err != nil {
+			return err
+		}
+	case "ntfy":
+		headers["Title"] = fmt.Sprintf("%s %s", event.Kind, event.State)
+		headers["Tags"] = "warning"
+		headers["Priority"] = "high"
+
+		if up {
+			headers["Tags"] = "white_check_mark"
+			headers["Priority"] = "default"
+		}
+
+		if target.Token != "" {
+			headers["Authorization"] = "Bearer " + target.Token
+		}
+
+		if event.Url != "" {
+			headers["Click"] = event.Url
+		}
+
+		body = []byte(event.Message())
+	case "gotify":
+		url = strings.TrimSuffix(url, "/") + "/message"
+		headers["Content-Type"] = "application/json"
+		headers["X-Gotify-Key"] = target.Token
+
+		priority := 8
+		if up {
+			priority = 4
+		}
+
+		if body, err = json.Marshal(map[string]any{
+			"title":    fmt.Sprintf("%s %s", event.Kind, event.State),
+			"message":  event.Message(),
+			"priority": priority,
+		}); err != nil {
+			return err
+		}
+	}
+
+	for name, value := range target.Headers {
+		headers[name] = value
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	res, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return fmt.Errorf("unexpected status %d: %s", res.StatusCode, message)
+	}
+
+	return nil
+}
+
+func renderWebhookBody(target notifyTarget, event NotifyEvent) ([]byte, error) {
+	if target.body == nil {
+		return json.Marshal(event)
+	}
+
+	var body bytes.Buffer
+	if err := target.body.Execute(&body, event); err != nil {
+		return nil, fmt.Errorf("could not render body: %w", err)
+	}
+
+	return body.Bytes(), nil
+}
@@ -0,0 +1,214 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type receivedRequest struct {
+	path   string
+	header http.Header
+	body   string
+}
+
+func newTestReceiver(t *testing.T) (*httptest.Server, func() []receivedRequest) {
+	t.Helper()
+
+	var (
+		mu       sync.Mutex
+		received []receivedRequest
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mu.Lock()
+		received = append(received, receivedRequest{path: r.URL.Path, header: r.Header, body: string(body)})
+		mu.Unlock()
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []receivedRequest {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return append([]receivedRequest(nil), received...)
+	}
+}
+
+func testNotifyConfig() notifyConfig {
+	return notifyConfig{
+		Interval:      duration(time.Minute),
+		Debounce:      duration(time.Minute),
+		FlapWindow:    duration(time.Hour),
+		FlapThreshold: 4,
+	}
+}
+
+func TestNewNotifierFromConfig_NonPositiveDurations(t *testing.T) {
+	for name, modify := range map[string]func(*notifyConfig){
+		"interval":   func(c *notifyConfig) { c.Interval = 0 },
+		"debounce":   func(c *notifyConfig) { c.Debounce = duration(-time.Minute) },
+		"flapWindow": func(c *notifyConfig) { c.FlapWindow = 0 },
+	} {
+		config := testNotifyConfig()
+		modify(&config)
+
+		if _, err := newNotifierFromConfig(config); err == nil {
+			t.Errorf("expected error for non-positive %s", name)
+		}
+	}
+}
+
+func TestNotifier_Debounce(t *testing.T) {
+	n, err := newNotifierFromConfig(testNotifyConfig())
+	if err != nil {
+		t.Fatalf("could not create notifier: %v", err)
+	}
+
+	start := time.Now()
+	observe := func(offset time.Duration, state subjectState) []NotifyEvent {
+		return n.observe(start.Add(offset), map[string]subjectSample{
+			"apps/a/web": {Kind: "app", Name: "Web", State: state},
+		})
+	}
+
+	if events := observe(0, stateUp); len(events) != 0 {
+		t.Fatalf("initial state must not notify, got %v", events)
+	}
+
+	// A short blip is swallowed by the debounce.
+	observe(30*time.Second, stateDown)
+	if events := observe(60*time.Second, stateUp); len(events) != 0 {
+		t.Fatalf("blip must not notify, got %v", events)
+	}
+
+	observe(2*time.Minute, stateDown)
+	events := observe(3*time.Minute, stateDown)
+	if len(events) != 1 || events[0].State != "down" || events[0].Previous != "up" {
+		t.Fatalf("expected a single down event, got %v", events)
+	}
+
+	if events := observe(4*time.Minute, stateDown); len(events) != 0 {
+		t.Fatalf("unchanged state must not notify, got %v", events)
+	}
+}
+
+func TestNotifier_FlapSuppression(t *testing.T) {
+	config := testNotifyConfig()
+	config.FlapThreshold = 2
+
+	n, err := newNotifierFromConfig(config)
+	if err != nil {
+		t.Fatalf("could not create notifier: %v", err)
+	}
+
+	start := time.Now()
+	var events []NotifyEvent
+
+	// Every state is sampled twice, so it persists for the debounce duration.
+	for i, state := range []subjectState{stateUp, stateUp, stateDown, stateDown, stateUp, stateUp, stateDown, stateDown, stateUp, stateUp, stateDown, stateDown} {
+		events = append(events, n.observe(start.Add(time.Duration(i)*time.Minute), map[string]subjectSample{
+			"nodes/a": {Kind: "node", Name: "a", State: state},
+		})...)
+	}
+
+	// down, up, then flapping; everything after is suppressed.
+	if len(events) != 3 || !events[2].Flapping || events[2].State != "down" {
+		t.Fatalf("expected two state events and one flapping event, got %v", events)
+	}
+
+	// Once the flap window passed, the current state is notified.
+	events = n.observe(start.Add(2*time.Hour), map[string]subjectSample{
+		"nodes/a": {Kind: "node", Name: "a", State: stateDown},
+	})
+	if len(events) != 1 || events[0].State != "down" {
+		t.Fatalf("expected down event after flapping, got %v", events)
+	}
+}
+
+func TestNotifier_Targets(t *testing.T) {
+	server, received := newTestReceiver(t)
+
+	config := testNotifyConfig()
+	config.Targets = []notifyTarget{
+		{Type: "webhook", Url: server.URL + "/hook", Body: `{"text": {{ .Message | toJson }}}`},
+		{Type: "webhook", Url: server.URL + "/raw", Headers: map[string]string{"X-Custom": "yes"}},
+		{Type: "ntfy", Url: server.URL + "/topic", Token: "secret"},
+		{Type: "gotify", Url: server.URL + "/", Token: "app-token"},
+	}
+
+	n, err := newNotifierFromConfig(config)
+	if err != nil {
+		t.Fatalf("could not create notifier: %v", err)
+	}
+
+	n.notifyAll(context.Background(), NotifyEvent{Kind: "app", Name: "Immich", State: "down", Previous: "up"})
+
+	requests := received()
+	if len(requests) != 4 {
+		t.Fatalf("got %d requests, want 4", len(requests))
+	}
+
+	byPath := make(map[string]receivedRequest)
+	for _, request := range requests {
+		byPath[request.path] = request
+	}
+
+	if got, want := byPath["/hook"].body, `{"text": "Immich is down"}`; got != want {
+		t.Errorf("webhook body = %s, want %s", got, want)
+	}
+
+	var raw NotifyEvent
+	if err := json.Unmarshal([]byte(byPath["/raw"].body), &raw); err != nil || raw.Name != "Immich" {
+		t.Errorf("raw webhook body = %s (%v)", byPath["/raw"].body, err)
+	}
+
+	if got := byPath["/raw"].header.Get("X-Custom"); got != "yes" {
+		t.Errorf("custom header = %q, want %q", got, "yes")
+	}
+
+	ntfy := byPath["/topic"]
+	if ntfy.body != "Immich is down" || ntfy.header.Get("Authorization") != "Bearer secret" || ntfy.header.Get("Priority") != "high" {
+		t.Errorf("unexpected ntfy request: %+v", ntfy)
+	}
+
+	gotify := byPath["/message"]
+	if gotify.header.Get("X-Gotify-Key") != "app-token" {
+		t.Errorf("unexpected gotify token %q", gotify.header.Get("X-Gotify-Key"))
+	}
+
+	var message struct {
+		Message  string `json:"message"`
+		Priority int    `json:"priority"`
+	}
+	if err := json.Unmarshal([]byte(gotify.body), &message); err != nil || message.Message != "Immich is down" || message.Priority != 8 {
+		t.Errorf("unexpected gotify body %s (%v)", gotify.body, err)
+	}
+}
+
+func TestNotifier_MaintenanceIsNotNotified(t *testing.T) {
+	n, err := newNotifierFromConfig(testNotifyConfig())
+	if err != nil {
+		t.Fatalf("could not create notifier: %v", err)
+	}
+
+	start := time.Now()
+	var events []NotifyEvent
+
+	for i, state := range []subjectState{stateUp, stateMaintenance, stateMaintenance, stateUp, stateDown, stateDown} {
+		events = append(events, n.observe(start.Add(time.Duration(i)*time.Minute), map[string]subjectSample{
+			"apps/a/web": {Kind: "app", Name: "Web", State: state},
+		})...)
+	}
+
+	if len(events) != 1 || events[0].State != "down" {
+		t.Fatalf("expected only the down event after maintenance, got %v", events)
+	}
+}
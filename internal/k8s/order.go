@@ -0,0 +1,226 @@
+package k8s
+
+import (
+	"cmp"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+const (
+	aOrder    = "glance/order"
+	aCategory = "glance/category"
+)
+
+// uncategorized is the title of the section of apps without a category.
+const uncategorized = "Other"
+
+// AppGroup is a titled section of apps.
+type AppGroup struct {
+	Title string
+	Apps  AppSlice
+	// CollapseAfter is the number of apps shown before the rest of the
+	// group is collapsed, or 0 to show all apps.
+	CollapseAfter int
+}
+
+// appComparators compare apps by the values of the sort-by parameter.
+var appComparators = map[string]func(a, b *App) int{
+	"name": func(a, b *App) int {
+		return 0 // Name is always used to break ties.
+	},
+	"namespace": func(a, b *App) int {
+		return strings.Compare(a.Workload.GetNamespace(), b.Workload.GetNamespace())
+	},
+	"status": func(a, b *App) int {
+		return cmp.Compare(appStatusRank(a), appStatusRank(b))
+	},
+	"kind": func(a, b *App) int {
+		return strings.Compare(a.Workload.GetKind(), b.Workload.GetKind())
+	},
+	// cpu and memory sort the heaviest apps first.
+	"cpu": func(a, b *App) int {
+		return cmp.Compare(b.Resources.CpuCores(), a.Resources.CpuCores())
+	},
+	"memory": func(a, b *App) int {
+		return cmp.Compare(b.Resources.MemBytes(), a.Resources.MemBytes())
+	},
+}
+
+// sortApps sorts apps by their glance/order annotation first, then by
+// sortBy and finally by name. Apps without an order come after all ordered
+// apps.
+func sortApps(apps AppSlice, sortBy string) error {
+	if sortBy == "" {
+		sortBy = "name"
+	}
+
+	compare, ok := appComparators[sortBy]
+	if !ok {
+		return fmt.Errorf("unknown sort-by %q", sortBy)
+	}
+
+	type order struct {
+		value int
+		ok    bool
+	}
+
+	orders := make(map[*App]order, len(apps))
+	for _, app := range apps {
+		value, ok := appOrder(app)
+		orders[app] = order{value, ok}
+	}
+
+	sort.Stable(apps)
+	slices.SortStableFunc(apps, func(a, b *App) int {
+		aOrder, bOrder := orders[a], orders[b]
+
+		switch {
+		case aOrder.ok && bOrder.ok && aOrder.value != bOrder.value:
+			return cmp.Compare(aOrder.value, bOrder.value)
+		case aOrder.ok && !bOrder.ok:
+			return -1
+		case !aOrder.ok && bOrder.ok:
+			return 1
+		default:
+			return compare(a, b)
+		}
+	})
+
+	return nil
+}
+
+func appOrder(app *App) (int, bool) {
+	value, ok := app.Annotations[aOrder]
+	if !ok {
+		return 0, false
+	}
+
+	order, err := strconv.Atoi(value)
+	if err != nil {
+		slog.Warn("invalid order", slog.String("app", app.key()), slog.String("order", value))
+		return 0, false
+	}
+
+	return order, true
+}
+
+// appStatusRank sorts unready apps first, followed by apps in maintenance.
+func appStatusRank(app *App) int {
+	switch {
+	case app.Maintenance() != nil:
+		return 1
+	case !app.Ready():
+		return 0
+	default:
+		return 2
+	}
+}
+
+// unhealthy reports whether the app is unready without being in maintenance.
+func (a *App) unhealthy() bool {
+	return appStatusRank(a) == 0
+}
+
+// pinUnhealthy moves unhealthy apps to the front, so they are neither cut
+// off by a limit nor collapsed. The order is otherwise kept.
+func pinUnhealthy(apps AppSlice) {
+	slices.SortStableFunc(apps, func(a, b *App) int {
+		switch {
+		case a.unhealthy() && !b.unhealthy():
+			return -1
+		case !a.unhealthy() && b.unhealthy():
+			return 1
+		default:
+			return 0
+		}
+	})
+}
+
+// limitApps returns at most limit apps, but never drops unhealthy apps.
+// Unhealthy apps have to be pinned before.
+func limitApps(apps AppSlice, limit int) AppSlice {
+	limit = max(limit, countUnhealthy(apps))
+
+	if limit <= 0 || len(apps) <= limit {
+		return apps
+	}
+
+	return apps[:limit]
+}
+
+func countUnhealthy(apps AppSlice) int {
+	return lo.CountBy(apps, (*App).unhealthy)
+}
+
+// collapseGroups sets the number of apps shown before each group collapses,
+// which is at least the number of unhealthy apps in the group.
+func collapseGroups(groups []AppGroup, collapseAfter int) {
+	if collapseAfter <= 0 {
+		return
+	}
+
+	for i := range groups {
+		groups[i].CollapseAfter = max(collapseAfter, countUnhealthy(groups[i].Apps))
+	}
+}
+
+// GroupBy splits the apps into sections by namespace or glance/category
+// annotation, keeping the order of apps within each section. An empty
+// groupBy returns a single untitled section.
+func (a AppSlice) GroupBy(groupBy string) ([]AppGroup, error) {
+	var title func(*App) string
+
+	switch groupBy {
+	case "":
+		return []AppGroup{{Apps: a}}, nil
+	case "namespace":
+		title = func(app *App) string {
+			return app.Workload.GetNamespace()
+		}
+	case "category":
+		title = func(app *App) string {
+			if category := app.Annotations[aCategory]; category != "" {
+				return category
+			}
+
+			return uncategorized
+		}
+	default:
+		return nil, fmt.Errorf("unknown group-by %q", groupBy)
+	}
+
+	var groups []AppGroup
+	indices := make(map[string]int)
+
+	for _, app := range a {
+		t := title(app)
+
+		i, ok := indices[t]
+		if !ok {
+			i = len(groups)
+			indices[t] = i
+			groups = append(groups, AppGroup{Title: t})
+		}
+
+		groups[i].Apps = append(groups[i].Apps, app)
+	}
+
+	slices.SortStableFunc(groups, func(a, b AppGroup) int {
+		switch {
+		case a.Title == uncategorized:
+			return 1
+		case b.Title == uncategorized:
+			return -1
+		default:
+			return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+		}
+	})
+
+	return groups, nil
+}
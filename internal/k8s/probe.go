@@ -0,0 +1,275 @@
+package k8s
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	aHealthUrl     = "glance/health-url"
+	aProbe         = "glance/probe"
+	aProbeTimeout  = "glance/probe-timeout"
+	aProbeStatus   = "glance/probe-status"
+	aProbeInsecure = "glance/probe-insecure"
+)
+
+const (
+	defaultProbeTimeout = 5 * time.Second
+	defaultProbeStatus  = "200-399"
+	probeConcurrency    = 8
+)
+
+// ProbeResult is the outcome of the last HTTP probe of an app.
+type ProbeResult struct {
+	Url        string
+	StatusCode int
+	Latency    time.Duration
+	Error      string
+	Time       time.Time
+	Ok         bool
+}
+
+// probeTarget describes how to probe a single app.
+type probeTarget struct {
+	key      string
+	url      string
+	timeout  time.Duration
+	accepted statusRanges
+	insecure bool
+}
+
+// prober periodically probes the urls of all apps and keeps the latest
+// result per app.
+type prober struct {
+	interval time.Duration
+	secure   *http.Client
+	insecure *http.Client
+
+	mu      sync.RWMutex
+	results map[string]ProbeResult
+}
+
+// newProber creates a prober from the GLANCE_PROBE_INTERVAL duration. An
+// empty interval disables probing.
+func newProber(interval string) (*prober, error) {
+	if interval == "" {
+		return nil, nil
+	}
+
+	duration, err := time.ParseDuration(interval)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse probe interval: %w", err)
+	}
+
+	if duration <= 0 {
+		return nil, fmt.Errorf("could not parse probe interval: %q is not positive", interval)
+	}
+
+	noRedirect := func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	insecureTransport := http.DefaultTransport.(*http.Transport).Clone()
+	insecureTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec // opt-in per app
+
+	return &prober{
+		interval: duration,
+		secure:   &http.Client{CheckRedirect: noRedirect},
+		insecure: &http.Client{CheckRedirect: noRedirect, Transport: insecureTransport},
+		results:  make(map[string]ProbeResult),
+	}, nil
+}
+
+// run probes all apps returned by apps once per interval until ctx is done.
+func (p *prober) run(ctx context.Context, apps func(context.Context) (AppSlice, error)) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if list, err := apps(ctx); err != nil {
+			slog.Warn("could not list apps to probe", slog.Any("err", err))
+		} else {
+			p.probeAll(ctx, list)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *prober) probeAll(ctx context.Context, apps AppSlice) {
+	var (
+		mu      sync.Mutex
+		results = make(map[string]ProbeResult)
+		group   errgroup.Group
+	)
+
+	group.SetLimit(probeConcurrency)
+
+	for _, app := range apps {
+		target, ok := newProbeTarget(app)
+		if !ok {
+			continue
+		}
+
+		group.Go(func() error {
+			result := p.probe(ctx, target)
+
+			mu.Lock()
+			results[target.key] = result
+			mu.Unlock()
+
+			return nil
+		})
+	}
+
+	_ = group.Wait()
+
+	p.mu.Lock()
+	p.results = results
+	p.mu.Unlock()
+}
+
+func (p *prober) probe(ctx context.Context, target probeTarget) ProbeResult {
+	ctx, cancel := context.WithTimeout(ctx, target.timeout)
+	defer cancel()
+
+	result := ProbeResult{Url: target.url, Time: time.Now()}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.url, nil)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	client := p.secure
+	if target.insecure {
+		client = p.insecure
+	}
+
+	res, err := client.Do(req)
+	result.Latency = time.Since(result.Time)
+
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		_ = res.Body.Close()
+
+		result.StatusCode = res.StatusCode
+		result.Ok = target.accepted.contains(res.StatusCode)
+	}
+
+	slog.Debug("probed app",
+		slog.String("app", target.key),
+		slog.String("url", target.url),
+		slog.Int("status", result.StatusCode),
+		slog.Duration("latency", result.Latency),
+		slog.String("err", result.Error),
+	)
+
+	return result
+}
+
+// result returns the latest probe result of the app with the given key.
+func (p *prober) result(key string) (*ProbeResult, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	result, ok := p.results[key]
+	return &result, ok
+}
+
+func newProbeTarget(app *App) (probeTarget, bool) {
+	if app.Annotations[aProbe] == "false" {
+		return probeTarget{}, false
+	}
+
+	target := probeTarget{
+		key:      app.key(),
+		url:      app.Annotations[aHealthUrl],
+		timeout:  defaultProbeTimeout,
+		insecure: app.Annotations[aProbeInsecure] == "true",
+	}
+
+	if target.url == "" {
+		target.url = app.Url()
+
+		// Ingress rules without a host yield urls like http:/// that cannot
+		// be probed.
+		if parsed, err := url.Parse(target.url); err != nil || parsed.Host == "" {
+			return probeTarget{}, false
+		}
+	}
+
+	if value, ok := app.Annotations[aProbeTimeout]; ok {
+		if timeout, err := time.ParseDuration(value); err == nil {
+			target.timeout = timeout
+		} else {
+			slog.Warn("invalid probe timeout", slog.String("app", target.key), slog.Any("err", err))
+		}
+	}
+
+	target.accepted, _ = parseStatusRanges(defaultProbeStatus)
+	if value, ok := app.Annotations[aProbeStatus]; ok {
+		if accepted, err := parseStatusRanges(value); err == nil {
+			target.accepted = accepted
+		} else {
+			slog.Warn("invalid probe status codes", slog.String("app", target.key), slog.Any("err", err))
+		}
+	}
+
+	return target, true
+}
+
+// statusRanges is a list of inclusive ranges of http status codes.
+type statusRanges [][2]int
+
+// parseStatusRanges parses a comma separated list of status codes and
+// ranges, e.g. "200-399,401".
+func parseStatusRanges(s string) (statusRanges, error) {
+	var ranges statusRanges
+
+	for _, part := range strings.Split(s, ",") {
+		lower, upper, isRange := strings.Cut(strings.TrimSpace(part), "-")
+		if !isRange {
+			upper = lower
+		}
+
+		from, err := strconv.Atoi(lower)
+		if err != nil {
+			return nil, fmt.Errorf("invalid status code %q", part)
+		}
+
+		to, err := strconv.Atoi(upper)
+		if err != nil {
+			return nil, fmt.Errorf("invalid status code %q", part)
+		}
+
+		ranges = append(ranges, [2]int{from, to})
+	}
+
+	return ranges, nil
+}
+
+func (r statusRanges) contains(statusCode int) bool {
+	for _, statusRange := range r {
+		if statusRange[0] <= statusCode && statusCode <= statusRange[1] {
+			return true
+		}
+	}
+
+	return false
+}
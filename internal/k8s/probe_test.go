@@ -0,0 +1,85 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseStatusRanges(t *testing.T) {
+	ranges, err := parseStatusRanges("200-299, 401")
+	if err != nil {
+		t.Fatalf("could not parse: %v", err)
+	}
+
+	for code, want := range map[int]bool{200: true, 299: true, 300: false, 401: true, 500: false} {
+		if got := ranges.contains(code); got != want {
+			t.Errorf("contains(%d) = %v, want %v", code, got, want)
+		}
+	}
+
+	if _, err := parseStatusRanges("2xx"); err == nil {
+		t.Fatalf("expected error for invalid status code")
+	}
+}
+
+func TestNewProber_NonPositiveInterval(t *testing.T) {
+	for _, interval := range []string{"0s", "-1m"} {
+		if _, err := newProber(interval); err == nil {
+			t.Errorf("expected error for interval %q", interval)
+		}
+	}
+}
+
+func TestProber_ProbeAll(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/healthz":
+			w.WriteHeader(http.StatusOK)
+		case "/login":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	p, err := newProber("1m")
+	if err != nil {
+		t.Fatalf("could not create prober: %v", err)
+	}
+
+	newApp := func(name string, annotations map[string]string) *App {
+		return &App{Workload: testDeployment("a", name, nil, nil), Annotations: annotations}
+	}
+
+	apps := AppSlice{
+		newApp("healthy", map[string]string{aUrl: server.URL, aHealthUrl: server.URL + "/healthz"}),
+		newApp("broken", map[string]string{aUrl: server.URL}),
+		newApp("login", map[string]string{aUrl: server.URL + "/login", aProbeStatus: "200-399,401"}),
+		newApp("disabled", map[string]string{aUrl: server.URL, aProbe: "false"}),
+		newApp("no-url", nil),
+		newApp("no-host", map[string]string{aUrl: "http:///"}),
+		newApp("no-host-health", map[string]string{aUrl: "http:///", aHealthUrl: server.URL + "/healthz"}),
+	}
+
+	p.probeAll(context.Background(), apps)
+
+	for name, want := range map[string]bool{"healthy": true, "broken": false, "login": true, "no-host-health": true} {
+		result, ok := p.result("a/" + name)
+		if !ok {
+			t.Fatalf("%s: no probe result", name)
+		}
+
+		if result.Ok != want {
+			t.Errorf("%s: ok = %v, want %v (status %d)", name, result.Ok, want, result.StatusCode)
+		}
+	}
+
+	for _, name := range []string{"disabled", "no-url", "no-host"} {
+		if _, ok := p.result("a/" + name); ok {
+			t.Errorf("%s: unexpected probe result", name)
+		}
+	}
+}
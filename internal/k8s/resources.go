@@ -0,0 +1,185 @@
+package k8s
+
+import (
+	"log/slog"
+	"sync/atomic"
+
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/lukasdietrich/glance-k8s/internal/k8s/api"
+)
+
+var trackedResources = []api.ResourceName{api.ResourceCPU, api.ResourceMemory}
+
+// Resources sums the usage, requests and limits of all running pods of an
+// app across its workload and dependencies. A resource is missing from
+// Requests or Limits, if any container does not specify it.
+type Resources struct {
+	Pods     int
+	Usage    api.ResourceList
+	Requests api.ResourceList
+	Limits   api.ResourceList
+}
+
+// CpuCores returns the cpu usage in cores.
+func (r *Resources) CpuCores() float64 {
+	if r == nil {
+		return 0
+	}
+
+	return r.Usage.Cpu().AsApproximateFloat64()
+}
+
+// MemBytes returns the memory usage in bytes.
+func (r *Resources) MemBytes() float64 {
+	if r == nil {
+		return 0
+	}
+
+	return r.Usage.Memory().AsApproximateFloat64()
+}
+
+// CpuRequest returns the summed cpu requests, or nil if unbounded.
+func (r *Resources) CpuRequest() *api.Quantity {
+	return lookupQuantity(r.Requests, api.ResourceCPU)
+}
+
+// CpuLimit returns the summed cpu limits, or nil if unbounded.
+func (r *Resources) CpuLimit() *api.Quantity {
+	return lookupQuantity(r.Limits, api.ResourceCPU)
+}
+
+// MemRequest returns the summed memory requests, or nil if unbounded.
+func (r *Resources) MemRequest() *api.Quantity {
+	return lookupQuantity(r.Requests, api.ResourceMemory)
+}
+
+// MemLimit returns the summed memory limits, or nil if unbounded.
+func (r *Resources) MemLimit() *api.Quantity {
+	return lookupQuantity(r.Limits, api.ResourceMemory)
+}
+
+func lookupQuantity(list api.ResourceList, name api.ResourceName) *api.Quantity {
+	quantity, ok := list[name]
+	if !ok {
+		return nil
+	}
+
+	return &quantity
+}
+
+// fetchState logs failures of an optional fetch only when its state changes,
+// instead of on every call, e.g. when the metrics-server is not installed.
+type fetchState struct {
+	failing atomic.Bool
+}
+
+func (f *fetchState) observe(name string, err error) {
+	switch {
+	case err != nil && !f.failing.Swap(true):
+		slog.Warn("could not fetch "+name, slog.Any("err", err))
+	case err != nil:
+		slog.Debug("could not fetch "+name, slog.Any("err", err))
+	case f.failing.Swap(false):
+		slog.Info("fetched " + name + " again")
+	}
+}
+
+// podMetricsFinder returns the metrics of all pods selected by a workload.
+type podMetricsFinder func(workload Workload) []api.PodMetrics
+
+func makePodMetricsFinder(podMetrics []api.PodMetrics) podMetricsFinder {
+	byNamespace := lo.GroupBy(podMetrics, func(metrics api.PodMetrics) string {
+		return metrics.Namespace
+	})
+
+	return func(workload Workload) []api.PodMetrics {
+		selector := workload.GetSpec().Selector
+		if selector == nil {
+			return nil
+		}
+
+		parsed, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil || parsed.Empty() {
+			return nil
+		}
+
+		return lo.Filter(byNamespace[workload.GetNamespace()], func(metrics api.PodMetrics, _ int) bool {
+			return parsed.Matches(labels.Set(metrics.Labels))
+		})
+	}
+}
+
+// appResources sums the resources of all pods of the workloads. It returns
+// nil, if no pod metrics were found.
+func appResources(workloads WorkloadSlice, findPodMetrics podMetricsFinder) *Resources {
+	resources := Resources{
+		Usage:    make(api.ResourceList),
+		Requests: make(api.ResourceList),
+		Limits:   make(api.ResourceList),
+	}
+
+	missingRequests := make(map[api.ResourceName]bool)
+	missingLimits := make(map[api.ResourceName]bool)
+
+	for _, workload := range workloads {
+		pods := findPodMetrics(workload)
+		if len(pods) == 0 {
+			continue
+		}
+
+		resources.Pods += len(pods)
+
+		for _, pod := range pods {
+			for _, container := range pod.Containers {
+				addResources(resources.Usage, container.Usage, 1)
+			}
+		}
+
+		for _, container := range workload.GetSpec().Template.Spec.Containers {
+			for _, name := range trackedResources {
+				if _, ok := container.Resources.Requests[name]; !ok {
+					missingRequests[name] = true
+				}
+
+				if _, ok := container.Resources.Limits[name]; !ok {
+					missingLimits[name] = true
+				}
+			}
+
+			addResources(resources.Requests, container.Resources.Requests, int64(len(pods)))
+			addResources(resources.Limits, container.Resources.Limits, int64(len(pods)))
+		}
+	}
+
+	if resources.Pods == 0 {
+		return nil
+	}
+
+	for name := range missingRequests {
+		delete(resources.Requests, name)
+	}
+
+	for name := range missingLimits {
+		delete(resources.Limits, name)
+	}
+
+	return &resources
+}
+
+// addResources adds the tracked resources of list times n to sum.
+func addResources(sum, list api.ResourceList, n int64) {
+	for _, name := range trackedResources {
+		quantity, ok := list[name]
+		if !ok {
+			continue
+		}
+
+		total := sum[name]
+		total.Add(*resource.NewMilliQuantity(quantity.MilliValue()*n, quantity.Format))
+		sum[name] = total
+	}
+}
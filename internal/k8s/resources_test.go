@@ -0,0 +1,94 @@
+package k8s
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/metrics/pkg/apis/metrics/v1beta1"
+
+	"github.com/lukasdietrich/glance-k8s/internal/k8s/api"
+)
+
+func testPodMetrics(namespace, name string, labels map[string]string, cpu, memory string) api.PodMetrics {
+	return api.PodMetrics{
+		ObjectMeta: api.ObjectMeta{Namespace: namespace, Name: name, Labels: labels},
+		Containers: []v1beta1.ContainerMetrics{{
+			Name: "app",
+			Usage: api.ResourceList{
+				api.ResourceCPU:    resource.MustParse(cpu),
+				api.ResourceMemory: resource.MustParse(memory),
+			},
+		}},
+	}
+}
+
+func TestAppResources(t *testing.T) {
+	web := testDeployment("a", "web", nil, nil).(*deployment)
+	web.Spec.Selector = &api.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+	web.Spec.Template.Spec.Containers = []api.Container{{
+		Name: "web",
+		Resources: corev1.ResourceRequirements{
+			Requests: api.ResourceList{api.ResourceCPU: resource.MustParse("100m"), api.ResourceMemory: resource.MustParse("64Mi")},
+			Limits:   api.ResourceList{api.ResourceMemory: resource.MustParse("128Mi")},
+		},
+	}}
+
+	db := testDeployment("a", "db", nil, nil).(*deployment)
+	db.Spec.Selector = &api.LabelSelector{MatchLabels: map[string]string{"app": "db"}}
+	db.Spec.Template.Spec.Containers = []api.Container{{Name: "db"}}
+
+	findPodMetrics := makePodMetricsFinder([]api.PodMetrics{
+		testPodMetrics("a", "web-1", map[string]string{"app": "web"}, "10m", "32Mi"),
+		testPodMetrics("a", "web-2", map[string]string{"app": "web"}, "20m", "32Mi"),
+		testPodMetrics("b", "web-1", map[string]string{"app": "web"}, "500m", "1Gi"),
+		testPodMetrics("a", "other", map[string]string{"app": "other"}, "500m", "1Gi"),
+	})
+
+	resources := appResources(WorkloadSlice{web}, findPodMetrics)
+	if resources == nil || resources.Pods != 2 {
+		t.Fatalf("resources = %+v, want 2 pods", resources)
+	}
+
+	tests := map[string]struct {
+		got  *api.Quantity
+		want string
+	}{
+		"cpu usage":      {resources.Usage.Cpu(), "30m"},
+		"memory usage":   {resources.Usage.Memory(), "64Mi"},
+		"cpu request":    {resources.CpuRequest(), "200m"},
+		"memory request": {resources.MemRequest(), "128Mi"},
+		"memory limit":   {resources.MemLimit(), "256Mi"},
+	}
+
+	for name, test := range tests {
+		if test.got == nil || test.got.Cmp(resource.MustParse(test.want)) != 0 {
+			t.Errorf("%s = %v, want %s", name, test.got, test.want)
+		}
+	}
+
+	if resources.CpuLimit() != nil {
+		t.Errorf("expected no cpu limit, got %v", resources.CpuLimit())
+	}
+
+	if resources := appResources(WorkloadSlice{db}, findPodMetrics); resources != nil {
+		t.Errorf("expected no resources without pod metrics, got %+v", resources)
+	}
+}
+
+func TestAddResources(t *testing.T) {
+	sum := api.ResourceList{api.ResourceMemory: resource.MustParse("1Gi")}
+
+	addResources(sum, api.ResourceList{
+		api.ResourceCPU:    resource.MustParse("250m"),
+		api.ResourceMemory: resource.MustParse("512Mi"),
+	}, 3)
+
+	if got := sum[api.ResourceCPU]; got.Cmp(resource.MustParse("750m")) != 0 {
+		t.Errorf("cpu = %s, want 750m", got.String())
+	}
+
+	if got := sum[api.ResourceMemory]; got.Cmp(resource.MustParse("2560Mi")) != 0 {
+		t.Errorf("memory = %s, want 2560Mi", got.String())
+	}
+}
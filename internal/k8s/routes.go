@@ -0,0 +1,187 @@
+package k8s
+
+import (
+	"github.com/lukasdietrich/glance-k8s/internal/k8s/api"
+)
+
+// routeWorkload adapts an Ingress or HTTPRoute without a matching workload,
+// e.g. one pointing at an ExternalName service. Every backend service counts
+// as a replica, which is ready if it is an ExternalName service or has at
+// least one ready endpoint.
+type routeWorkload struct {
+	*api.ObjectMeta
+
+	kind     string
+	resource string
+	status   WorkloadStatus
+}
+
+// key identifies the route, prefixed with its resource, so it never shares a
+// key with a workload in the probes or the history.
+func (r *routeWorkload) key() string {
+	return r.resource + "/" + resourceFullname(r)
+}
+
+func (r *routeWorkload) GetKind() string {
+	return r.kind
+}
+
+func (r *routeWorkload) GetSpec() WorkloadSpec {
+	return WorkloadSpec{}
+}
+
+func (r *routeWorkload) GetImages() []Image {
+	return nil
+}
+
+func (r *routeWorkload) GetVersion() string {
+	return r.Labels[lVersion]
+}
+
+func (r *routeWorkload) GetStatus() WorkloadStatus {
+	return r.status
+}
+
+// serviceReadiness reports whether services, keyed by their full name, are
+// able to serve traffic. A nil serviceReadiness is unknown and reports all
+// services as ready, so route apps are not shown as down.
+type serviceReadiness map[string]bool
+
+func newServiceReadiness(services []api.Service, endpointSlices []api.EndpointSlice) serviceReadiness {
+	readiness := make(serviceReadiness, len(services))
+
+	for _, service := range services {
+		readiness[resourceFullname(&service)] = service.Spec.Type == api.ServiceTypeExternalName
+	}
+
+	for _, endpointSlice := range endpointSlices {
+		name, ok := endpointSlice.Labels[api.LabelServiceName]
+		if !ok {
+			continue
+		}
+
+		for _, endpoint := range endpointSlice.Endpoints {
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				readiness[endpointSlice.Namespace+"/"+name] = true
+			}
+		}
+	}
+
+	return readiness
+}
+
+func (r serviceReadiness) status(namespace string, names []string) WorkloadStatus {
+	var status WorkloadStatus
+
+	for _, name := range names {
+		status.Replicas++
+
+		if r == nil || r[namespace+"/"+name] {
+			status.ReadyReplicas++
+		}
+	}
+
+	return status
+}
+
+// buildRouteApps creates apps from ingresses and httpRoutes annotated with
+// glance/name, which are not already part of an app in apps.
+func buildRouteApps(apps AppSlice, ingresses []api.Ingress, httpRoutes []api.HTTPRoute, readiness serviceReadiness) AppSlice {
+	used := make(map[string]bool, len(apps))
+
+	for _, app := range apps {
+		used[app.key()] = true
+
+		if app.Ingress != nil {
+			used["ingresses/"+resourceFullname(app.Ingress)] = true
+		}
+
+		if app.HTTPRoute != nil {
+			used["httproutes/"+resourceFullname(app.HTTPRoute)] = true
+		}
+	}
+
+	var routeApps AppSlice
+
+	add := func(workload *routeWorkload) bool {
+		if _, ok := workload.Annotations[aName]; !ok || used[workload.key()] {
+			return false
+		}
+
+		used[workload.key()] = true
+		return true
+	}
+
+	for _, ingress := range ingresses {
+		workload := &routeWorkload{
+			ObjectMeta: &ingress.ObjectMeta,
+			kind:       "Ingress",
+			resource:   "ingresses",
+			status:     readiness.status(ingress.Namespace, ingressServiceNames(ingress)),
+		}
+
+		if add(workload) {
+			routeApps = append(routeApps, &App{Workload: workload, Ingress: &ingress})
+		}
+	}
+
+	for _, httpRoute := range httpRoutes {
+		workload := &routeWorkload{
+			ObjectMeta: &httpRoute.ObjectMeta,
+			kind:       "HTTPRoute",
+			resource:   "httproutes",
+			status:     httpRouteStatus(httpRoute, readiness),
+		}
+
+		if add(workload) {
+			routeApps = append(routeApps, &App{Workload: workload, HTTPRoute: &httpRoute})
+		}
+	}
+
+	return routeApps
+}
+
+func ingressServiceNames(ingress api.Ingress) []string {
+	var names []string
+
+	if backend := ingress.Spec.DefaultBackend; backend != nil && backend.Service != nil {
+		names = append(names, backend.Service.Name)
+	}
+
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+
+		for _, path := range rule.HTTP.Paths {
+			if path.Backend.Service != nil {
+				names = append(names, path.Backend.Service.Name)
+			}
+		}
+	}
+
+	return names
+}
+
+func httpRouteStatus(httpRoute api.HTTPRoute, readiness serviceReadiness) WorkloadStatus {
+	var status WorkloadStatus
+
+	for _, rule := range httpRoute.Spec.Rules {
+		for _, backendRef := range rule.BackendRefs {
+			if backendRef.Kind != nil && *backendRef.Kind != "Service" {
+				continue
+			}
+
+			namespace := httpRoute.Namespace
+			if backendRef.Namespace != nil {
+				namespace = string(*backendRef.Namespace)
+			}
+
+			backendStatus := readiness.status(namespace, []string{string(backendRef.Name)})
+			status.Replicas += backendStatus.Replicas
+			status.ReadyReplicas += backendStatus.ReadyReplicas
+		}
+	}
+
+	return status
+}
@@ -0,0 +1,98 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/lukasdietrich/glance-k8s/internal/k8s/api"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+func testIngress(name, service string, annotations map[string]string) api.Ingress {
+	return api.Ingress{
+		ObjectMeta: api.ObjectMeta{Namespace: "home", Name: name, Annotations: annotations},
+		Spec: networkingv1.IngressSpec{
+			DefaultBackend: &networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{Name: service},
+			},
+		},
+	}
+}
+
+func TestBuildRouteApps(t *testing.T) {
+	services := []api.Service{
+		{ObjectMeta: api.ObjectMeta{Namespace: "home", Name: "nas"}, Spec: corev1.ServiceSpec{Type: api.ServiceTypeExternalName}},
+		{ObjectMeta: api.ObjectMeta{Namespace: "home", Name: "assistant"}},
+		{ObjectMeta: api.ObjectMeta{Namespace: "home", Name: "printer"}},
+	}
+
+	ready := true
+	endpointSlices := []api.EndpointSlice{
+		{
+			ObjectMeta: api.ObjectMeta{Namespace: "home", Name: "assistant-1", Labels: map[string]string{api.LabelServiceName: "assistant"}},
+			Endpoints:  []discoveryv1.Endpoint{{Conditions: discoveryv1.EndpointConditions{Ready: &ready}}},
+		},
+	}
+
+	ingresses := []api.Ingress{
+		testIngress("nas", "nas", map[string]string{aName: "NAS"}),
+		testIngress("assistant", "assistant", map[string]string{aName: "Home Assistant"}),
+		testIngress("printer", "printer", map[string]string{aName: "Printer"}),
+		testIngress("unnamed", "nas", nil),
+		testIngress("web", "web", map[string]string{aName: "Web"}),
+	}
+
+	apps := AppSlice{{Workload: testDeployment("home", "web", nil, nil), Ingress: &ingresses[4]}}
+
+	routeApps := buildRouteApps(apps, ingresses, nil, newServiceReadiness(services, endpointSlices))
+	if len(routeApps) != 3 {
+		t.Fatalf("len = %d, want 3", len(routeApps))
+	}
+
+	tests := map[string]bool{
+		"ingresses/home/nas":       true,
+		"ingresses/home/assistant": true,
+		"ingresses/home/printer":   false,
+	}
+
+	for _, app := range routeApps {
+		app.Annotations = app.Workload.GetAnnotations()
+
+		want, ok := tests[app.key()]
+		if !ok {
+			t.Fatalf("unexpected app %q", app.key())
+		}
+
+		if got := app.Ready(); got != want {
+			t.Errorf("%s: ready = %v, want %v", app.key(), got, want)
+		}
+
+		if app.Url() == "" {
+			t.Errorf("%s: expected an url", app.key())
+		}
+	}
+}
+
+func TestBuildRouteApps_UnknownReadiness(t *testing.T) {
+	ingresses := []api.Ingress{testIngress("printer", "printer", map[string]string{aName: "Printer"})}
+
+	routeApps := buildRouteApps(nil, ingresses, nil, nil)
+	if len(routeApps) != 1 || !routeApps[0].Ready() {
+		t.Fatalf("route apps = %v, want a single ready app", routeApps)
+	}
+}
+
+func TestBuildRouteApps_IngressAndHTTPRouteWithSameName(t *testing.T) {
+	ingresses := []api.Ingress{testIngress("web", "web", map[string]string{aName: "Web"})}
+	httpRoutes := []api.HTTPRoute{{ObjectMeta: api.ObjectMeta{Namespace: "home", Name: "web", Annotations: map[string]string{aName: "Web"}}}}
+
+	routeApps := buildRouteApps(nil, ingresses, httpRoutes, nil)
+	if len(routeApps) != 2 {
+		t.Fatalf("len = %d, want 2", len(routeApps))
+	}
+
+	if routeApps[0].key() != "ingresses/home/web" || routeApps[1].key() != "httproutes/home/web" {
+		t.Errorf("keys = %q, %q", routeApps[0].key(), routeApps[1].key())
+	}
+}
@@ -0,0 +1,138 @@
+package k8s
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/samber/lo"
+	"sigs.k8s.io/yaml"
+)
+
+// staticKind is the kind of apps defined in the static apps config.
+const staticKind = "External"
+
+// staticApp is an app living outside of the cluster, e.g. a NAS or router.
+type staticApp struct {
+	Id          string            `json:"id"`
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace"`
+	Icon        string            `json:"icon"`
+	Url         string            `json:"url"`
+	Description string            `json:"description"`
+	HealthUrl   string            `json:"healthUrl"`
+	Annotations map[string]string `json:"annotations"`
+	Labels      map[string]string `json:"labels"`
+}
+
+// loadStaticApps reads the static apps from the YAML file at filename. An
+// empty filename disables static apps.
+func loadStaticApps(filename string) ([]staticWorkload, error) {
+	if filename == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("could not read static apps: %w", err)
+	}
+
+	var apps []staticApp
+	if err := yaml.Unmarshal(content, &apps); err != nil {
+		return nil, fmt.Errorf("could not parse static apps: %w", err)
+	}
+
+	workloads := make([]staticWorkload, len(apps))
+	seen := make(map[string]bool, len(apps))
+
+	for i, app := range apps {
+		if app.Name == "" {
+			return nil, fmt.Errorf("static app %d has no name", i)
+		}
+
+		if app.Id == "" {
+			app.Id = strings.ToLower(strings.Join(strings.Fields(app.Name), "-"))
+		}
+
+		workload := newStaticWorkload(app)
+
+		key := resourceFullname(workload)
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate static app %q", key)
+		}
+
+		seen[key] = true
+		workloads[i] = workload
+	}
+
+	return workloads, nil
+}
+
+// staticWorkload adapts a static app to a workload, so it can be displayed,
+// filtered and probed like any discovered app. It is always ready, unless a
+// probe fails.
+type staticWorkload struct {
+	id          string
+	namespace   string
+	annotations map[string]string
+	labels      map[string]string
+}
+
+func newStaticWorkload(app staticApp) staticWorkload {
+	annotations := lo.OmitByValues(map[string]string{
+		aName:        app.Name,
+		aIcon:        app.Icon,
+		aUrl:         app.Url,
+		aDescription: app.Description,
+		aHealthUrl:   app.HealthUrl,
+	}, []string{""})
+
+	return staticWorkload{
+		id:          app.Id,
+		namespace:   app.Namespace,
+		annotations: lo.Assign(annotations, app.Annotations),
+		labels:      app.Labels,
+	}
+}
+
+// External reports whether the app is defined in the static apps config.
+func (a *App) External() bool {
+	_, ok := a.Workload.(staticWorkload)
+	return ok
+}
+
+func (staticWorkload) GetKind() string {
+	return staticKind
+}
+
+func (s staticWorkload) GetAnnotations() map[string]string {
+	return lo.Assign(s.annotations)
+}
+
+func (s staticWorkload) GetLabels() map[string]string {
+	return s.labels
+}
+
+func (s staticWorkload) GetName() string {
+	return s.id
+}
+
+func (s staticWorkload) GetNamespace() string {
+	return s.namespace
+}
+
+func (staticWorkload) GetSpec() WorkloadSpec {
+	return WorkloadSpec{}
+}
+
+func (staticWorkload) GetImages() []Image {
+	return nil
+}
+
+func (s staticWorkload) GetVersion() string {
+	return s.labels[lVersion]
+}
+
+func (staticWorkload) GetStatus() WorkloadStatus {
+	return WorkloadStatus{Replicas: 1, ReadyReplicas: 1}
+}
@@ -0,0 +1,70 @@
+package k8s
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadStaticApps(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "apps.yaml")
+	config := `
+- name: Home NAS
+  icon: di:synology
+  url: https://nas.example.org
+  healthUrl: https://nas.example.org/health
+  annotations:
+    glance/category: Infrastructure
+- name: Router
+  id: gateway
+  namespace: network
+`
+	if err := os.WriteFile(filename, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	workloads, err := loadStaticApps(filename)
+	if err != nil {
+		t.Fatalf("could not load static apps: %v", err)
+	}
+
+	if len(workloads) != 2 {
+		t.Fatalf("len = %d, want 2", len(workloads))
+	}
+
+	nas := &App{Workload: workloads[0], Annotations: workloads[0].GetAnnotations()}
+	if got := nas.key(); got != "static//home-nas" {
+		t.Errorf("key = %q, want %q", got, "static//home-nas")
+	}
+
+	if got := nas.Name(); got != "Home NAS" {
+		t.Errorf("name = %q, want %q", got, "Home NAS")
+	}
+
+	if !nas.External() || !nas.Ready() {
+		t.Errorf("expected an external, ready app")
+	}
+
+	if target, ok := newProbeTarget(nas); !ok || target.url != "https://nas.example.org/health" {
+		t.Errorf("probe target = %+v, want the health url", target)
+	}
+
+	if got := nas.Annotations[aCategory]; got != "Infrastructure" {
+		t.Errorf("category = %q, want %q", got, "Infrastructure")
+	}
+
+	if got := resourceFullname(workloads[1]); got != "network/gateway" {
+		t.Errorf("fullname = %q, want %q", got, "network/gateway")
+	}
+}
+
+func TestLoadStaticApps_Duplicate(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "apps.yaml")
+	if err := os.WriteFile(filename, []byte("- name: NAS\n- name: nas\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := loadStaticApps(filename); err == nil {
+		t.Fatal("expected an error for duplicate apps")
+	}
+}
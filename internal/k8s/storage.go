@@ -0,0 +1,124 @@
+package k8s
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/lukasdietrich/glance-k8s/internal/k8s/api"
+)
+
+const defaultStorageThreshold = 80
+
+type StorageOptions struct {
+	Namespace    []string
+	StorageClass []string
+	// Threshold is the usage in percent above which a volume is highlighted.
+	Threshold int
+}
+
+// Volume is a persistent volume claim and its usage.
+type Volume struct {
+	Name         string
+	Namespace    string
+	StorageClass string
+	Phase        string
+	// Capacity is the capacity of the claim, or of the filesystem if the
+	// kubelet reports it.
+	Capacity *api.Quantity
+	// Used is nil, if the claim is not mounted or the kubelet does not
+	// report stats for it.
+	Used  *api.Quantity
+	Ratio float64
+	Full  bool
+}
+
+// Bound reports whether the claim is bound to a volume.
+func (v Volume) Bound() bool {
+	return v.Phase == string(api.ClaimBound)
+}
+
+// Percent returns the ratio in percent, capped at 100.
+func (v Volume) Percent() float64 {
+	return min(v.Ratio*100, 100)
+}
+
+// Volumes returns all persistent volume claims with their usage, the fullest
+// first.
+func (c *Cluster) Volumes(ctx context.Context, opts StorageOptions) ([]Volume, error) {
+	claims, err := c.client.PersistentVolumeClaims(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch persistent volume claims: %w", err)
+	}
+
+	stats, err := c.client.VolumeStats(ctx)
+	c.volumeStatsState.observe("volume stats", err)
+
+	claims = lo.Filter(claims, func(claim api.PersistentVolumeClaim, _ int) bool {
+		return (len(opts.Namespace) == 0 || slices.Contains(opts.Namespace, claim.Namespace)) &&
+			(len(opts.StorageClass) == 0 || slices.Contains(opts.StorageClass, claimStorageClass(claim)))
+	})
+
+	volumes := buildVolumes(claims, stats, cmp.Or(opts.Threshold, defaultStorageThreshold))
+
+	slices.SortStableFunc(volumes, func(a, b Volume) int {
+		return cmp.Or(
+			cmp.Compare(b.Ratio, a.Ratio),
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+
+	return volumes, nil
+}
+
+func buildVolumes(claims []api.PersistentVolumeClaim, stats []api.VolumeStats, threshold int) []Volume {
+	statsByClaim := make(map[string]api.VolumeStats, len(stats))
+	for _, volumeStats := range stats {
+		statsByClaim[volumeStats.PVCRef.Namespace+"/"+volumeStats.PVCRef.Name] = volumeStats
+	}
+
+	return lo.Map(claims, func(claim api.PersistentVolumeClaim, _ int) Volume {
+		volume := Volume{
+			Name:         claim.Name,
+			Namespace:    claim.Namespace,
+			StorageClass: claimStorageClass(claim),
+			Phase:        string(claim.Status.Phase),
+			Capacity:     lookupQuantity(claim.Status.Capacity, api.ResourceStorage),
+		}
+
+		volumeStats, ok := statsByClaim[resourceFullname(&claim)]
+		if !ok || volumeStats.UsedBytes == nil {
+			return volume
+		}
+
+		volume.Used = bytesQuantity(*volumeStats.UsedBytes)
+
+		if volumeStats.CapacityBytes != nil {
+			volume.Capacity = bytesQuantity(*volumeStats.CapacityBytes)
+		}
+
+		if volume.Capacity != nil && !volume.Capacity.IsZero() {
+			volume.Ratio = volume.Used.AsApproximateFloat64() / volume.Capacity.AsApproximateFloat64()
+			volume.Full = volume.Percent() >= float64(threshold)
+		}
+
+		return volume
+	})
+}
+
+func claimStorageClass(claim api.PersistentVolumeClaim) string {
+	if claim.Spec.StorageClassName == nil {
+		return ""
+	}
+
+	return *claim.Spec.StorageClassName
+}
+
+func bytesQuantity(bytes uint64) *api.Quantity {
+	return resource.NewQuantity(int64(bytes), resource.BinarySI)
+}
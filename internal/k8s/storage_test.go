@@ -0,0 +1,50 @@
+package k8s
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/lukasdietrich/glance-k8s/internal/k8s/api"
+)
+
+func TestBuildVolumes(t *testing.T) {
+	claim := func(name string, phase corev1.PersistentVolumeClaimPhase, capacity string) api.PersistentVolumeClaim {
+		return api.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "media", Name: name},
+			Status: corev1.PersistentVolumeClaimStatus{
+				Phase:    phase,
+				Capacity: api.ResourceList{api.ResourceStorage: resource.MustParse(capacity)},
+			},
+		}
+	}
+
+	bytes := func(n uint64) *uint64 { return &n }
+
+	claims := []api.PersistentVolumeClaim{
+		claim("full", api.ClaimBound, "10Gi"),
+		claim("empty", api.ClaimBound, "10Gi"),
+		claim("pending", corev1.ClaimPending, "1Gi"),
+	}
+
+	stats := []api.VolumeStats{
+		{PVCRef: &api.PVCReference{Namespace: "media", Name: "full"}, UsedBytes: bytes(9 << 30), CapacityBytes: bytes(10 << 30)},
+		{PVCRef: &api.PVCReference{Namespace: "media", Name: "empty"}, UsedBytes: bytes(1 << 30)},
+	}
+
+	volumes := buildVolumes(claims, stats, 80)
+
+	if full := volumes[0]; full.Ratio != 0.9 || !full.Full {
+		t.Errorf("full = %+v, want 90%% and above threshold", full)
+	}
+
+	if empty := volumes[1]; empty.Ratio != 0.1 || empty.Full {
+		t.Errorf("empty = %+v, want 10%% of the claim capacity", empty)
+	}
+
+	if pending := volumes[2]; pending.Used != nil || pending.Bound() {
+		t.Errorf("pending = %+v, want no usage and unbound", pending)
+	}
+}
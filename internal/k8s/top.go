@@ -0,0 +1,227 @@
+package k8s
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/samber/lo"
+
+	"github.com/lukasdietrich/glance-k8s/internal/k8s/api"
+)
+
+const defaultTopLimit = 10
+
+var podShowIfCache = newCompileCache(compileCacheSize, compileShowIf[podEnv]).publish("cache.podShowIf")
+
+type TopOptions struct {
+	// By is the resource to rank by, either cpu (default) or memory.
+	By string
+	// Of is either pods (default) or apps.
+	Of        string
+	Limit     int
+	Namespace []string
+	ShowIf    []string
+}
+
+// TopEntry is the usage of a single pod or app.
+type TopEntry struct {
+	Name      string
+	Namespace string
+	Icon      string
+	Resource  string
+	Usage     *api.Quantity
+	// Limit is the summed limit of all containers, or nil if any container
+	// is unbounded.
+	Limit *api.Quantity
+	// Ratio is the usage relative to the limit, or relative to the heaviest
+	// entry if there is no limit.
+	Ratio float64
+}
+
+// Percent returns the ratio in percent, capped at 100.
+func (e TopEntry) Percent() float64 {
+	return min(e.Ratio*100, 100)
+}
+
+// podEnv is the environment of show-if expressions of the top widget, when
+// listing pods.
+type podEnv struct {
+	Name        string            `expr:"name"`
+	Namespace   string            `expr:"namespace"`
+	Node        string            `expr:"node"`
+	Labels      map[string]string `expr:"labels"`
+	Annotations map[string]string `expr:"annotations"`
+	Cpu         float64           `expr:"cpu"`
+	Memory      float64           `expr:"memory"`
+}
+
+// Top returns the heaviest pods or apps by cpu or memory usage.
+func (c *Cluster) Top(ctx context.Context, opts TopOptions) ([]TopEntry, error) {
+	resource := api.ResourceCPU
+
+	switch opts.By {
+	case "", "cpu":
+	case "memory":
+		resource = api.ResourceMemory
+	default:
+		return nil, fmt.Errorf("unknown resource %q", opts.By)
+	}
+
+	var (
+		entries []TopEntry
+		err     error
+	)
+
+	switch opts.Of {
+	case "", "pods":
+		entries, err = c.topPods(ctx, resource, opts)
+	case "apps":
+		entries, err = c.topApps(ctx, resource, opts)
+	default:
+		return nil, fmt.Errorf("unknown kind %q", opts.Of)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	entries = lo.Filter(entries, func(entry TopEntry, _ int) bool {
+		return len(opts.Namespace) == 0 || slices.Contains(opts.Namespace, entry.Namespace)
+	})
+
+	slices.SortStableFunc(entries, func(a, b TopEntry) int {
+		return b.Usage.Cmp(*a.Usage)
+	})
+
+	limit := cmp.Or(opts.Limit, defaultTopLimit)
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	rateEntries(entries)
+	return entries, nil
+}
+
+func (c *Cluster) topPods(ctx context.Context, resource api.ResourceName, opts TopOptions) ([]TopEntry, error) {
+	pods, err := c.client.Pods(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch pods: %w", err)
+	}
+
+	podMetrics, err := c.client.PodMetrics(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch pod metrics: %w", err)
+	}
+
+	programs, err := showIfPrograms(podShowIfCache, opts.ShowIf)
+	if err != nil {
+		return nil, fmt.Errorf("could not build show-if filter: %w", err)
+	}
+
+	podsByName := lo.SliceToMap(pods, func(pod api.Pod) (string, api.Pod) {
+		return resourceFullname(&pod), pod
+	})
+
+	var entries []TopEntry
+
+	for _, metrics := range podMetrics {
+		pod, ok := podsByName[resourceFullname(&metrics)]
+		if !ok {
+			continue
+		}
+
+		usage := make(api.ResourceList)
+		for _, container := range metrics.Containers {
+			addResources(usage, container.Usage, 1)
+		}
+
+		env := podEnv{
+			Name:        pod.Name,
+			Namespace:   pod.Namespace,
+			Node:        pod.Spec.NodeName,
+			Labels:      pod.Labels,
+			Annotations: pod.Annotations,
+			Cpu:         usage.Cpu().AsApproximateFloat64(),
+			Memory:      usage.Memory().AsApproximateFloat64(),
+		}
+
+		if !matchesShowIf(programs, &env) {
+			continue
+		}
+
+		entries = append(entries, TopEntry{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+			Resource:  string(resource),
+			Usage:     lookupQuantity(usage, resource),
+			Limit:     podLimit(pod, resource),
+		})
+	}
+
+	return lo.Filter(entries, func(entry TopEntry, _ int) bool { return entry.Usage != nil }), nil
+}
+
+// podLimit sums the limits of all containers of the pod, or returns nil if
+// any container is unbounded.
+func podLimit(pod api.Pod, resource api.ResourceName) *api.Quantity {
+	var limit api.Quantity
+
+	for _, container := range pod.Spec.Containers {
+		quantity, ok := container.Resources.Limits[resource]
+		if !ok {
+			return nil
+		}
+
+		limit.Add(quantity)
+	}
+
+	return &limit
+}
+
+func (c *Cluster) topApps(ctx context.Context, resource api.ResourceName, opts TopOptions) ([]TopEntry, error) {
+	apps, err := c.Apps(ctx, AppsOptions{ShowIf: opts.ShowIf})
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []TopEntry
+
+	for _, app := range apps {
+		if app.Resources == nil {
+			continue
+		}
+
+		entries = append(entries, TopEntry{
+			Name:      app.Name(),
+			Namespace: app.Workload.GetNamespace(),
+			Icon:      app.Icon(),
+			Resource:  string(resource),
+			Usage:     lookupQuantity(app.Resources.Usage, resource),
+			Limit:     lookupQuantity(app.Resources.Limits, resource),
+		})
+	}
+
+	return lo.Filter(entries, func(entry TopEntry, _ int) bool { return entry.Usage != nil }), nil
+}
+
+// rateEntries sets the ratio of each entry to its limit. Entries without a
+// limit are rated against the heaviest entry.
+func rateEntries(entries []TopEntry) {
+	var heaviest float64
+	for _, entry := range entries {
+		heaviest = max(heaviest, entry.Usage.AsApproximateFloat64())
+	}
+
+	for i, entry := range entries {
+		usage := entry.Usage.AsApproximateFloat64()
+
+		switch {
+		case entry.Limit != nil && !entry.Limit.IsZero():
+			entries[i].Ratio = usage / entry.Limit.AsApproximateFloat64()
+		case heaviest > 0:
+			entries[i].Ratio = usage / heaviest
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/expr-lang/expr/vm"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/lukasdietrich/glance-k8s/internal/k8s/api"
+)
+
+func TestPodLimit(t *testing.T) {
+	limited := corev1.ResourceRequirements{Limits: api.ResourceList{api.ResourceMemory: resource.MustParse("128Mi")}}
+
+	pod := api.Pod{Spec: corev1.PodSpec{Containers: []api.Container{{Resources: limited}, {Resources: limited}}}}
+	if got := podLimit(pod, api.ResourceMemory); got == nil || got.Cmp(resource.MustParse("256Mi")) != 0 {
+		t.Errorf("limit = %v, want 256Mi", got)
+	}
+
+	pod.Spec.Containers = append(pod.Spec.Containers, api.Container{})
+	if got := podLimit(pod, api.ResourceMemory); got != nil {
+		t.Errorf("limit = %v, want unbounded", got)
+	}
+}
+
+func TestRateEntries(t *testing.T) {
+	quantity := func(s string) *api.Quantity {
+		q := resource.MustParse(s)
+		return &q
+	}
+
+	entries := []TopEntry{
+		{Usage: quantity("400Mi")},
+		{Usage: quantity("100Mi"), Limit: quantity("200Mi")},
+		{Usage: quantity("100Mi")},
+		{Usage: quantity("300Mi"), Limit: quantity("200Mi")},
+	}
+
+	rateEntries(entries)
+
+	for i, want := range []float64{1, 0.5, 0.25, 1.5} {
+		if got := entries[i].Ratio; got != want {
+			t.Errorf("%d: ratio = %v, want %v", i, got, want)
+		}
+	}
+
+	if got := entries[3].Percent(); got != 100 {
+		t.Errorf("percent = %v, want 100", got)
+	}
+}
+
+func TestCompilePodShowIf(t *testing.T) {
+	program, err := compileShowIf[podEnv](`namespace == "media" and memory > 1024 and labels["app"] == "web"`)
+	if err != nil {
+		t.Fatalf("could not compile: %v", err)
+	}
+
+	env := podEnv{Namespace: "media", Memory: 2048, Labels: map[string]string{"app": "web"}}
+	if !matchesShowIf([]*vm.Program{program}, &env) {
+		t.Error("expected pod to match")
+	}
+
+	if _, err := compileShowIf[podEnv](`ready`); err == nil {
+		t.Error("expected an error for an unknown variable")
+	}
+}
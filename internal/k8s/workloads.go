@@ -32,11 +32,15 @@ func (w WorkloadSlice) Swap(i int, j int) {
 }
 
 type Workload interface {
+	GetKind() string
 	GetAnnotations() map[string]string
+	GetLabels() map[string]string
 	GetName() string
 	GetNamespace() string
 	GetSpec() WorkloadSpec
 	GetStatus() WorkloadStatus
+	GetImages() []Image
+	GetVersion() string
 }
 
 type WorkloadSpec struct {
@@ -57,6 +61,10 @@ type deployment struct {
 	api.Deployment
 }
 
+func (deployment) GetKind() string {
+	return "Deployment"
+}
+
 func (d deployment) GetAnnotations() map[string]string {
 	return lo.Assign(d.Spec.Template.GetAnnotations(), d.Deployment.GetAnnotations())
 }
@@ -68,6 +76,14 @@ func (d deployment) GetSpec() WorkloadSpec {
 	}
 }
 
+func (d deployment) GetImages() []Image {
+	return templateImages(d.Spec.Template)
+}
+
+func (d deployment) GetVersion() string {
+	return templateVersion(d.Labels, d.Spec.Template)
+}
+
 func (d deployment) GetStatus() WorkloadStatus {
 	return WorkloadStatus{
 		Replicas:      d.Status.Replicas,
@@ -79,6 +95,10 @@ type statefulSet struct {
 	api.StatefulSet
 }
 
+func (statefulSet) GetKind() string {
+	return "StatefulSet"
+}
+
 func (s statefulSet) GetAnnotations() map[string]string {
 	return lo.Assign(s.Spec.Template.GetAnnotations(), s.StatefulSet.GetAnnotations())
 }
@@ -90,6 +110,14 @@ func (s statefulSet) GetSpec() WorkloadSpec {
 	}
 }
 
+func (s statefulSet) GetImages() []Image {
+	return templateImages(s.Spec.Template)
+}
+
+func (s statefulSet) GetVersion() string {
+	return templateVersion(s.Labels, s.Spec.Template)
+}
+
 func (s statefulSet) GetStatus() WorkloadStatus {
 	return WorkloadStatus{
 		Replicas:      s.Status.Replicas,
@@ -101,6 +129,10 @@ type daemonSet struct {
 	api.DaemonSet
 }
 
+func (daemonSet) GetKind() string {
+	return "DaemonSet"
+}
+
 func (d daemonSet) GetAnnotations() map[string]string {
 	return lo.Assign(d.Spec.Template.GetAnnotations(), d.DaemonSet.GetAnnotations())
 }
@@ -112,6 +144,14 @@ func (d daemonSet) GetSpec() WorkloadSpec {
 	}
 }
 
+func (d daemonSet) GetImages() []Image {
+	return templateImages(d.Spec.Template)
+}
+
+func (d daemonSet) GetVersion() string {
+	return templateVersion(d.Labels, d.Spec.Template)
+}
+
 func (d daemonSet) GetStatus() WorkloadStatus {
 	return WorkloadStatus{
 		Replicas:      d.Status.DesiredNumberScheduled,